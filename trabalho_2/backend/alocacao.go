@@ -0,0 +1,404 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AllocationBody é o corpo de POST /memory/allocation
+// Cada processo da carga ocupa memory unidades de begin até begin + duration
+type AllocationBody struct {
+	Alg        string      `json:"alg"`        // first, best, worst, next ou buddy
+	MemorySize int         `json:"memorySize"` // Tamanho total da memória
+	MinBlock   int         `json:"minBlock"`   // Menor bloco do buddy (padrão 1)
+	Input      []Processes `json:"input"`
+}
+
+// Alocador decide onde cada requisição é colocada na memória e como as lacunas são juntadas
+type Alocador interface {
+	alocar(tamanho int) int // Índice de um segmento livre do tamanho do bloco a ocupar, ou -1 se não couber
+	liberar(i int)          // Junta o segmento recém-liberado com as lacunas vizinhas
+}
+
+// SegmentoMemoria é um trecho contíguo da memória, livre ou ocupado por um processo
+type SegmentoMemoria struct {
+	inicio     int
+	tamanho    int
+	processo   *Processo // nil = lacuna
+	solicitado int       // Quanto o processo pediu; o restante do segmento é fragmentação interna
+}
+
+// livre indica se o segmento é uma lacuna
+func (seg SegmentoMemoria) livre() bool {
+	return seg.processo == nil
+}
+
+// SimuladorAlocacao gerencia a memória enquanto os processos chegam e terminam
+type SimuladorAlocacao struct {
+	tamanho    int               // Tamanho total da memória
+	segmentos  []SegmentoMemoria // Ordenados por endereço, cobrindo toda a memória
+	processos  []*Processo       // Ordenados pelo instante de chegada
+	alocacoes  map[int]int       // Id do processo -> posição em resultados
+	instante   int               // Instante do evento sendo atendido
+	mapas      []MapaMemoria     // Estado da memória após cada instante com eventos
+	eventos    []EventoAlocacao
+	resultados []AlocacaoProcesso
+}
+
+// BlocoMemoria é um segmento no mapa da memória
+type BlocoMemoria struct {
+	Inicio     int    `json:"inicio"`
+	Tamanho    int    `json:"tamanho"`
+	Processo   string `json:"processo,omitempty"`   // Vazio = lacuna
+	Solicitado int    `json:"solicitado,omitempty"` // Quanto o processo pediu do bloco
+}
+
+// MapaMemoria é o estado da memória em um instante
+type MapaMemoria struct {
+	Instante            int            `json:"instante"`
+	Blocos              []BlocoMemoria `json:"blocos"`
+	Ocupada             int            `json:"ocupada"`
+	Livre               int            `json:"livre"`
+	Lacunas             int            `json:"lacunas"`
+	MaiorLacuna         int            `json:"maiorLacuna"`
+	FragmentacaoInterna int            `json:"fragmentacaoInterna"` // Espaço dentro dos blocos que os processos não pediram
+	FragmentacaoExterna float64        `json:"fragmentacaoExterna"` // Fração da memória livre que está fora da maior lacuna
+}
+
+// EventoAlocacao descreve uma alocação, liberação ou falha
+type EventoAlocacao struct {
+	Instante int    `json:"instante"`
+	Tipo     string `json:"tipo"` // "alocacao", "liberacao" ou "falha"
+	Processo string `json:"processo"`
+	Tamanho  int    `json:"tamanho"`
+	Endereco *int   `json:"endereco,omitempty"`
+	Motivo   string `json:"motivo,omitempty"`
+}
+
+// AlocacaoProcesso resume o que aconteceu com a memória de cada processo
+type AlocacaoProcesso struct {
+	Processo  string `json:"processo"`
+	Indice    int    `json:"indice"`
+	Id        string `json:"id,omitempty"`
+	Memoria   int    `json:"memoria"`
+	Chegada   int    `json:"chegada"`
+	Liberacao int    `json:"liberacao"`          // Chegada + duração
+	Endereco  *int   `json:"endereco,omitempty"` // nil se a alocação falhou
+	Bloco     int    `json:"bloco,omitempty"`    // Tamanho do bloco ocupado (maior que a memória pedida no buddy)
+	Falha     string `json:"falha,omitempty"`
+}
+
+// ResultadoAlocacao reúne a linha do tempo da memória e as métricas de fragmentação
+type ResultadoAlocacao struct {
+	Algoritmo                 string             `json:"algoritmo"`
+	Memoria                   int                `json:"memoria"`
+	Processos                 []AlocacaoProcesso `json:"processos"`
+	Eventos                   []EventoAlocacao   `json:"eventos"`
+	Mapas                     []MapaMemoria      `json:"mapas"`
+	Falhas                    int                `json:"falhas"`
+	FalhasPorFragmentacao     int                `json:"falhasPorFragmentacao"` // Havia memória livre suficiente, mas não contígua
+	FragmentacaoInternaMaxima int                `json:"fragmentacaoInternaMaxima"`
+	FragmentacaoExternaMaxima float64            `json:"fragmentacaoExternaMaxima"`
+	UtilizacaoMedia           float64            `json:"utilizacaoMedia"` // Fração média da memória ocupada, ponderada pelo tempo
+}
+
+// Motivos de falha de alocação
+const (
+	falhaMemoriaInsuficiente = "memória insuficiente"
+	falhaFragmentacao        = "fragmentação externa"
+)
+
+// maxMemoria limita o tamanho da memória simulada
+const maxMemoria = 1 << 30
+
+// Dependendo do tipo escolhido, cria o alocador correspondente
+func novoAlocador(tipo string, s *SimuladorAlocacao, minBloco int) (Alocador, error) {
+	switch tipo {
+	case "first":
+		return &FirstFit{s}, nil
+	case "best":
+		return &BestFit{s}, nil
+	case "worst":
+		return &WorstFit{s}, nil
+	case "next":
+		return &NextFit{s, 0}, nil
+	case "buddy":
+		return &Buddy{s, minBloco}, nil
+	default:
+		return nil, fmt.Errorf("algoritmo de alocação inválido")
+	}
+}
+
+func novoSimuladorAlocacao(tamanho int, processos []*Processo) *SimuladorAlocacao {
+	s := &SimuladorAlocacao{
+		tamanho:   tamanho,
+		segmentos: []SegmentoMemoria{{inicio: 0, tamanho: tamanho}},
+		processos: processos,
+		alocacoes: map[int]int{},
+		eventos:   []EventoAlocacao{},
+		mapas:     []MapaMemoria{},
+	}
+	for i, p := range processos {
+		s.alocacoes[p.id] = i
+		s.resultados = append(s.resultados, AlocacaoProcesso{
+			Processo:  p.rotulo(),
+			Indice:    p.id,
+			Id:        p.idExterno,
+			Memoria:   p.memoria,
+			Chegada:   p.instanteCriacao,
+			Liberacao: p.instanteCriacao + p.duracao,
+		})
+	}
+	return s
+}
+
+// dividir separa o segmento livre i em um bloco de tamanho unidades e uma lacuna com o restante
+func (s *SimuladorAlocacao) dividir(i, tamanho int) int {
+	seg := &s.segmentos[i]
+	if seg.tamanho > tamanho {
+		resto := SegmentoMemoria{inicio: seg.inicio + tamanho, tamanho: seg.tamanho - tamanho}
+		seg.tamanho = tamanho
+		s.segmentos = slices.Insert(s.segmentos, i+1, resto)
+	}
+	return i
+}
+
+// juntar une as lacunas i e i+1
+func (s *SimuladorAlocacao) juntar(i int) int {
+	s.segmentos[i].tamanho += s.segmentos[i+1].tamanho
+	s.segmentos = slices.Delete(s.segmentos, i+1, i+2)
+	return i
+}
+
+// juntarVizinhos une a lacuna i com as lacunas adjacentes
+func (s *SimuladorAlocacao) juntarVizinhos(i int) {
+	if i+1 < len(s.segmentos) && s.segmentos[i+1].livre() {
+		s.juntar(i)
+	}
+	if i > 0 && s.segmentos[i-1].livre() {
+		s.juntar(i - 1)
+	}
+}
+
+// executar percorre os eventos em ordem de tempo: em cada instante, primeiro libera a
+// memória dos processos que terminaram e depois atende as chegadas na ordem da carga
+func (s *SimuladorAlocacao) executar(alg Alocador) {
+	proximo := 0 // Próximo processo a chegar
+	for {
+		instante, ok := s.proximoEvento(proximo)
+		if !ok {
+			break
+		}
+		s.instante = instante
+
+		s.liberarTerminados(alg)
+		for proximo < len(s.processos) && s.processos[proximo].instanteCriacao == s.instante {
+			s.atender(alg, s.processos[proximo])
+			proximo++
+		}
+		s.registrarMapa()
+	}
+}
+
+// proximoEvento devolve o instante da próxima chegada ou do próximo término de um processo alocado
+func (s *SimuladorAlocacao) proximoEvento(proximo int) (int, bool) {
+	instante, ok := 0, false
+	if proximo < len(s.processos) {
+		instante, ok = s.processos[proximo].instanteCriacao, true
+	}
+	for _, seg := range s.segmentos {
+		if seg.livre() {
+			continue
+		}
+		if fim := seg.processo.instanteCriacao + seg.processo.duracao; !ok || fim < instante {
+			instante, ok = fim, true
+		}
+	}
+	return instante, ok
+}
+
+// liberarTerminados devolve a memória dos processos que terminam neste instante, em ordem de endereço
+func (s *SimuladorAlocacao) liberarTerminados(alg Alocador) {
+	for {
+		i := slices.IndexFunc(s.segmentos, func(seg SegmentoMemoria) bool {
+			return !seg.livre() && seg.processo.instanteCriacao+seg.processo.duracao == s.instante
+		})
+		if i == -1 {
+			return
+		}
+
+		seg := &s.segmentos[i]
+		endereco := seg.inicio
+		s.eventos = append(s.eventos, EventoAlocacao{
+			Instante: s.instante,
+			Tipo:     "liberacao",
+			Processo: seg.processo.rotulo(),
+			Tamanho:  seg.tamanho,
+			Endereco: &endereco,
+		})
+		seg.processo, seg.solicitado = nil, 0
+		alg.liberar(i)
+	}
+}
+
+// atender tenta alocar a memória pedida pelo processo
+func (s *SimuladorAlocacao) atender(alg Alocador, p *Processo) {
+	r := &s.resultados[s.alocacoes[p.id]]
+
+	i := -1
+	if p.memoria <= s.tamanho {
+		i = alg.alocar(p.memoria)
+	}
+	if i == -1 {
+		r.Falha = falhaMemoriaInsuficiente
+		if livre, _ := s.lacunas(); livre >= p.memoria {
+			r.Falha = falhaFragmentacao
+		}
+		s.eventos = append(s.eventos, EventoAlocacao{
+			Instante: s.instante,
+			Tipo:     "falha",
+			Processo: p.rotulo(),
+			Tamanho:  p.memoria,
+			Motivo:   r.Falha,
+		})
+		return
+	}
+
+	seg := &s.segmentos[i]
+	seg.processo, seg.solicitado = p, p.memoria
+	endereco := seg.inicio
+	r.Endereco, r.Bloco = &endereco, seg.tamanho
+	s.eventos = append(s.eventos, EventoAlocacao{
+		Instante: s.instante,
+		Tipo:     "alocacao",
+		Processo: p.rotulo(),
+		Tamanho:  seg.tamanho,
+		Endereco: &endereco,
+	})
+}
+
+// lacunas devolve o total de memória livre e o tamanho da maior lacuna
+func (s *SimuladorAlocacao) lacunas() (int, int) {
+	livre, maior := 0, 0
+	for _, seg := range s.segmentos {
+		if seg.livre() {
+			livre += seg.tamanho
+			maior = max(maior, seg.tamanho)
+		}
+	}
+	return livre, maior
+}
+
+// registrarMapa registra o estado da memória neste instante
+func (s *SimuladorAlocacao) registrarMapa() {
+	m := MapaMemoria{Instante: s.instante, Blocos: make([]BlocoMemoria, len(s.segmentos))}
+	for i, seg := range s.segmentos {
+		m.Blocos[i] = BlocoMemoria{Inicio: seg.inicio, Tamanho: seg.tamanho, Solicitado: seg.solicitado}
+		if seg.livre() {
+			m.Lacunas++
+		} else {
+			m.Blocos[i].Processo = seg.processo.rotulo()
+			m.FragmentacaoInterna += seg.tamanho - seg.solicitado
+		}
+	}
+
+	m.Livre, m.MaiorLacuna = s.lacunas()
+	m.Ocupada = s.tamanho - m.Livre
+	if m.Livre > 0 {
+		m.FragmentacaoExterna = float64(m.Livre-m.MaiorLacuna) / float64(m.Livre)
+	}
+	s.mapas = append(s.mapas, m)
+}
+
+// resultado monta o resultado da simulação
+func (s *SimuladorAlocacao) resultado(algoritmo string) ResultadoAlocacao {
+	r := ResultadoAlocacao{
+		Algoritmo: algoritmo,
+		Memoria:   s.tamanho,
+		Processos: s.resultados,
+		Eventos:   s.eventos,
+		Mapas:     s.mapas,
+	}
+
+	for _, p := range s.resultados {
+		if p.Falha != "" {
+			r.Falhas++
+		}
+		if p.Falha == falhaFragmentacao {
+			r.FalhasPorFragmentacao++
+		}
+	}
+
+	// Cada mapa vale até o instante do mapa seguinte
+	ocupacao := 0
+	for i, m := range s.mapas {
+		r.FragmentacaoInternaMaxima = max(r.FragmentacaoInternaMaxima, m.FragmentacaoInterna)
+		r.FragmentacaoExternaMaxima = max(r.FragmentacaoExternaMaxima, m.FragmentacaoExterna)
+		if i+1 < len(s.mapas) {
+			ocupacao += m.Ocupada * (s.mapas[i+1].Instante - m.Instante)
+		}
+	}
+	if n := len(s.mapas); n > 1 {
+		if duracao := s.mapas[n-1].Instante - s.mapas[0].Instante; duracao > 0 {
+			r.UtilizacaoMedia = float64(ocupacao) / float64(duracao*s.tamanho)
+		}
+	}
+	return r
+}
+
+// potenciaDeDois indica se n é uma potência de dois
+func potenciaDeDois(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// validarAlocacao confere os parâmetros da simulação de alocação contígua
+func validarAlocacao(body AllocationBody) error {
+	if body.MemorySize <= 0 || body.MemorySize > maxMemoria {
+		return fmt.Errorf("memorySize deve estar entre 1 e %d", maxMemoria)
+	}
+	if body.MinBlock < 0 {
+		return fmt.Errorf("minBlock não pode ser negativo")
+	}
+	if body.Alg == "buddy" {
+		if !potenciaDeDois(body.MemorySize) {
+			return fmt.Errorf("no buddy, memorySize deve ser uma potência de dois")
+		}
+		if body.MinBlock > 0 && (!potenciaDeDois(body.MinBlock) || body.MinBlock > body.MemorySize) {
+			return fmt.Errorf("minBlock deve ser uma potência de dois menor ou igual a memorySize")
+		}
+	}
+	if err := limites.validar(ContextBody{Input: body.Input}); err != nil {
+		return err
+	}
+	if err := validarProcessos(body.Input); err != nil {
+		return err
+	}
+	for i, p := range body.Input {
+		if p.Memory <= 0 {
+			return fmt.Errorf("o processo %d não informa a memória (memory)", i+1)
+		}
+	}
+	return nil
+}
+
+// processAllocation executa a simulação de alocação contígua sobre a carga de processos
+func processAllocation(body AllocationBody) (ResultadoAlocacao, error) {
+	processos, err := lerEntradas(ContextBody{Input: body.Input})
+	if err != nil {
+		return ResultadoAlocacao{}, err
+	}
+
+	minBloco := body.MinBlock
+	if minBloco == 0 {
+		minBloco = 1
+	}
+
+	s := novoSimuladorAlocacao(body.MemorySize, processos)
+	alg, err := novoAlocador(body.Alg, s, minBloco)
+	if err != nil {
+		return ResultadoAlocacao{}, err
+	}
+
+	s.executar(alg)
+	return s.resultado(body.Alg), nil
+}
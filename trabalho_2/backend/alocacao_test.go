@@ -0,0 +1,157 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// enderecos devolve onde cada processo foi colocado, ou o motivo da falha
+func enderecos(r ResultadoAlocacao) map[string]any {
+	m := map[string]any{}
+	for _, p := range r.Processos {
+		if p.Endereco != nil {
+			m[p.Processo] = *p.Endereco
+		} else {
+			m[p.Processo] = p.Falha
+		}
+	}
+	return m
+}
+
+func TestAlgoritmosDeAlocacao(t *testing.T) {
+	// Em t=0: A[0,2) B[2,5) C[5,6) X[6,7) e lacuna [7,10)
+	// Em t=1 A e C saem, deixando lacunas de 2, 1 e 3 para D (2); em t=2 chega E (1)
+	entrada := []Processes{
+		{Begin: 0, Duration: 1, Priority: 1, Memory: 2, Name: "A"},
+		{Begin: 0, Duration: 10, Priority: 1, Memory: 3, Name: "B"},
+		{Begin: 0, Duration: 1, Priority: 1, Memory: 1, Name: "C"},
+		{Begin: 0, Duration: 10, Priority: 1, Memory: 1, Name: "X"},
+		{Begin: 1, Duration: 10, Priority: 1, Memory: 2, Name: "D"},
+		{Begin: 2, Duration: 10, Priority: 1, Memory: 1, Name: "E"},
+	}
+	casos := []struct {
+		alg  string
+		d, e int
+	}{
+		{"first", 0, 5}, // Primeira lacuna que cabe
+		{"best", 0, 5},  // Lacunas exatas: 2 para D e 1 para E
+		{"worst", 7, 0}, // Maior lacuna: 3 para D e depois a de 2 para E
+		{"next", 7, 9},  // Continua depois de X e depois de D
+	}
+
+	for _, c := range casos {
+		r, err := processAllocation(AllocationBody{Alg: c.alg, MemorySize: 10, Input: entrada})
+		if err != nil {
+			t.Fatal(err)
+		}
+		esperado := map[string]any{"A": 0, "B": 2, "C": 5, "X": 6, "D": c.d, "E": c.e}
+		if obtido := enderecos(r); !reflect.DeepEqual(obtido, esperado) {
+			t.Errorf("%s: %v, esperado %v", c.alg, obtido, esperado)
+		}
+	}
+}
+
+func TestFragmentacaoExterna(t *testing.T) {
+	// Em t=2 A sai e sobram lacunas de 3 em [0,3) e de 1 em [9,10)
+	// O first fit põe D na lacuna de 3 e E (3) não cabe, embora haja 3 unidades livres
+	entrada := []Processes{
+		{Begin: 0, Duration: 2, Priority: 1, Memory: 3, Name: "A"},
+		{Begin: 0, Duration: 10, Priority: 1, Memory: 2, Name: "B"},
+		{Begin: 0, Duration: 10, Priority: 1, Memory: 4, Name: "C"},
+		{Begin: 2, Duration: 10, Priority: 1, Memory: 1, Name: "D"},
+		{Begin: 3, Duration: 5, Priority: 1, Memory: 3, Name: "E"},
+	}
+	casos := []struct {
+		alg                 string
+		e                   any
+		falhas              int
+		fragmentacaoExterna float64
+	}{
+		{"first", falhaFragmentacao, 1, 1.0 / 3},
+		{"best", 0, 0, 0},
+	}
+
+	for _, c := range casos {
+		r, err := processAllocation(AllocationBody{Alg: c.alg, MemorySize: 10, Input: entrada})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := enderecos(r)["E"]; e != c.e {
+			t.Errorf("%s: E em %v, esperado %v", c.alg, e, c.e)
+		}
+		if r.Falhas != c.falhas || r.FalhasPorFragmentacao != c.falhas || r.FragmentacaoExternaMaxima != c.fragmentacaoExterna {
+			t.Errorf("%s: %d falhas (%d por fragmentação), fragmentação externa %v; esperado %d e %v",
+				c.alg, r.Falhas, r.FalhasPorFragmentacao, r.FragmentacaoExternaMaxima, c.falhas, c.fragmentacaoExterna)
+		}
+	}
+
+	// Maior que a memória inteira, a falha é por falta de memória
+	r, _ := processAllocation(AllocationBody{Alg: "first", MemorySize: 10, Input: []Processes{{Begin: 0, Duration: 1, Priority: 1, Memory: 11}}})
+	if r.Falhas != 1 || r.FalhasPorFragmentacao != 0 || r.Processos[0].Falha != falhaMemoriaInsuficiente {
+		t.Errorf("falha %+v", r.Processos[0])
+	}
+}
+
+func TestBuddy(t *testing.T) {
+	// A (3) e C (2) recebem blocos de 4 e 2; B (5) recebe o de 8
+	// Ao sair C, o bloco de 2 volta a se juntar com o companheiro até formar [0,8)
+	entrada := []Processes{
+		{Begin: 0, Duration: 1, Priority: 1, Memory: 3, Name: "A"},
+		{Begin: 0, Duration: 5, Priority: 1, Memory: 5, Name: "B"},
+		{Begin: 0, Duration: 2, Priority: 1, Memory: 2, Name: "C"},
+		{Begin: 3, Duration: 2, Priority: 1, Memory: 7, Name: "D"},
+	}
+	r, err := processAllocation(AllocationBody{Alg: "buddy", MemorySize: 16, Input: entrada})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapas := []struct {
+		instante    int
+		blocos      []BlocoMemoria
+		fragInterna int
+	}{
+		{0, []BlocoMemoria{{0, 4, "A", 3}, {4, 2, "C", 2}, {6, 2, "", 0}, {8, 8, "B", 5}}, 4},
+		{1, []BlocoMemoria{{0, 4, "", 0}, {4, 2, "C", 2}, {6, 2, "", 0}, {8, 8, "B", 5}}, 3},
+		{2, []BlocoMemoria{{0, 8, "", 0}, {8, 8, "B", 5}}, 3},
+		{3, []BlocoMemoria{{0, 8, "D", 7}, {8, 8, "B", 5}}, 4},
+		{5, []BlocoMemoria{{0, 16, "", 0}}, 0},
+	}
+	if len(r.Mapas) != len(mapas) {
+		t.Fatalf("%d mapas, esperado %d", len(r.Mapas), len(mapas))
+	}
+	for i, m := range mapas {
+		obtido := r.Mapas[i]
+		if obtido.Instante != m.instante || !reflect.DeepEqual(obtido.Blocos, m.blocos) || obtido.FragmentacaoInterna != m.fragInterna {
+			t.Errorf("mapa em t=%d: %+v (fragmentação interna %d), esperado %+v (%d)",
+				obtido.Instante, obtido.Blocos, obtido.FragmentacaoInterna, m.blocos, m.fragInterna)
+		}
+	}
+	if r.FragmentacaoInternaMaxima != 4 || r.Falhas != 0 {
+		t.Errorf("fragmentação interna máxima %d, %d falhas", r.FragmentacaoInternaMaxima, r.Falhas)
+	}
+	if b := r.Processos[0].Bloco; b != 4 {
+		t.Errorf("bloco de A %d, esperado 4", b)
+	}
+}
+
+func TestValidarAlocacao(t *testing.T) {
+	processo := []Processes{{Begin: 0, Duration: 1, Priority: 1, Memory: 4}}
+	casos := []struct {
+		nome string
+		body AllocationBody
+		erro bool
+	}{
+		{"válido", AllocationBody{Alg: "first", MemorySize: 10, Input: processo}, false},
+		{"buddy válido", AllocationBody{Alg: "buddy", MemorySize: 16, MinBlock: 4, Input: processo}, false},
+		{"sem memória", AllocationBody{Alg: "first", Input: processo}, true},
+		{"buddy sem potência de dois", AllocationBody{Alg: "buddy", MemorySize: 10, Input: processo}, true},
+		{"minBlock inválido", AllocationBody{Alg: "buddy", MemorySize: 16, MinBlock: 3, Input: processo}, true},
+		{"processo sem memory", AllocationBody{Alg: "first", MemorySize: 10, Input: []Processes{{Begin: 0, Duration: 1, Priority: 1}}}, true},
+	}
+	for _, c := range casos {
+		if err := validarAlocacao(c.body); (err != nil) != c.erro {
+			t.Errorf("%s: erro %v", c.nome, err)
+		}
+	}
+}
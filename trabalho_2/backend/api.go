@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -14,87 +21,661 @@ type ContextBody struct{
 	Quantum int `json:"quantum"`
 	Aging int `json:"aging"`
 	Input []Processes `json:"input"`
+	Trace bool `json:"trace"` // Inclui no resultado a explicação de cada decisão do escalonador
+	PriorityOrder string `json:"priorityOrder"` // "higher" (padrão): maior número = maior prioridade; "lower": o contrário
+	TieBreak []string `json:"tieBreak"` // Cadeia de desempate: "arrival", "id", "remaining" e "random"
+	Seed *int64 `json:"seed"` // Semente do desempate "random"
+	AgingStrategy string `json:"agingStrategy"` // "tick", "quantum" ou "threshold"; habilita envelhecimento em PSP, PCPP, SJF e SRTF
+	AgingThreshold int `json:"agingThreshold"` // Espera contínua que dispara a estratégia "threshold"
+	AgingReset bool `json:"agingReset"` // Volta à prioridade original quando o processo ganha a CPU
+	StarvationThreshold int `json:"starvationThreshold"` // Espera contínua acima da qual o processo é marcado como em inanição
+	ThroughputWindow int `json:"throughputWindow"` // Tamanho das janelas em que a vazão é calculada
+	MaxTime int `json:"maxTime"` // Horizonte da simulação; não pode passar do limite do servidor
+	TimeoutMs int `json:"timeoutMs"` // Tempo de relógio máximo da simulação; não pode passar do limite do servidor
+	Rounding *int `json:"rounding"` // Casas decimais de tempoMedioVida e tempoMedioEspera (padrão 2; negativo = sem arredondamento)
+	MemorySize int `json:"memorySize"` // Memória total; habilita o controle de admissão pela memória de cada processo
+	Admission string `json:"admission"` // Política do escalonador de longo prazo: "fifo" (padrão) ou "firstFit"
+	Swapping bool `json:"swapping"` // Habilita o escalonador de médio prazo (swap out de processos prontos)
+	SwapAfter int `json:"swapAfter"` // Espera por memória que dispara o swap out (padrão: quantum)
+	LockProtocol string `json:"lockProtocol"` // Protocolo dos recursos das seções críticas: "none" (padrão), "inheritance" ou "ceiling"
+	Resources map[string]int `json:"resources"` // Instâncias de cada recurso das seções críticas (padrão 1)
+	DeadlockRecovery string `json:"deadlockRecovery"` // Recuperação de deadlock: "none" (padrão), "abort" ou "rollback"
+	Semaphores map[string]int `json:"semaphores"` // Valor inicial de cada semáforo usado pelos programas
+	Scenario string `json:"scenario"` // Cenário de sincronização pronto: "producer-consumer", "readers-writers" ou "dining-philosophers"
+	ThreadModel string `json:"threadModel"` // Mapeamento das threads nas entidades do kernel: "1:1" (padrão), "N:1" ou "M:N"
+	KernelThreads int `json:"kernelThreads"` // Entidades do kernel por processo no modelo M:N (padrão 2)
+	UserScheduler string `json:"userScheduler"` // Escalonador de usuário das threads de cada entidade: "fifo" (padrão) ou "rr"
+	UserQuantum int `json:"userQuantum"` // Quantum do escalonador de usuário "rr" (padrão 1)
 }
 
 type Processes struct{
 	Begin int  `json:"begin"`
 	Duration int `json:"duration"`
 	Priority int `json:"priority"`
+	Name string `json:"name,omitempty"` // Nome opcional, usado no diagrama no lugar de P<id>
+	Id string `json:"id,omitempty"` // Identificador estável vindo do catálogo de jobs do usuário
+	User string `json:"user,omitempty"`
+	Group string `json:"group,omitempty"`
+	Class string `json:"class,omitempty"` // Classe do processo (ex: interativo, batch)
+	Labels map[string]string `json:"labels,omitempty"` // Rótulos livres, devolvidos sem alteração nos resultados
+	Memory int `json:"memory,omitempty"` // Memória que o processo ocupa enquanto está no sistema
+	CriticalSections []CriticalSection `json:"criticalSections,omitempty"` // Trechos da execução que usam recursos compartilhados
+	Program []ProgramStep `json:"program,omitempty"` // Passos compute, P, V e io; a duração é a soma dos compute
+	Threads []ThreadSpec `json:"threads,omitempty"` // Threads do processo; a duração é a soma das threads
+	Children []ForkSpec `json:"children,omitempty"` // Filhos criados por fork() durante a execução
+	WaitAt []int `json:"waitAt,omitempty"` // Pontos da execução em que o processo chama wait() e espera os filhos vivos
 }
 
 
 
 func main(){
 
+	entrada := flag.String("entrada", "", "arquivo de carga (txt, csv, json ou yaml) para simular sem iniciar o servidor")
+	alg := flag.String("alg", "fcfs", "algoritmo usado com -entrada")
+	quantum := flag.Int("quantum", 2, "quantum usado com -entrada")
+	aging := flag.Int("aging", 1, "aging usado com -entrada")
+	flag.IntVar(&limites.TempoMaximo, "tempo-maximo", limites.TempoMaximo, "maior horizonte de simulação aceito, em unidades de tempo")
+	flag.IntVar(&limites.MaxProcessos, "max-processos", limites.MaxProcessos, "maior quantidade de processos por simulação")
+	flag.DurationVar(&limites.TempoLimite, "tempo-limite", limites.TempoLimite, "tempo de relógio máximo de cada simulação (0 desativa)")
+	workers := flag.Int("workers", runtime.NumCPU(), "quantidade de jobs executados ao mesmo tempo")
+	filaJobs := flag.Int("fila-jobs", 100, "quantidade máxima de jobs esperando execução")
+	caminhoHistorico := flag.String("historico", "historico.db", "arquivo do histórico de execuções (vazio desativa)")
+	maxHistorico := flag.Int("historico-max", 1000, "quantidade de execuções guardadas no histórico; as mais antigas são apagadas")
+	flag.Parse()
+
+	// Com um arquivo de entrada, simula direto pela linha de comando e imprime o resultado em JSON
+	if *entrada != "" {
+		if err := simularArquivo(*entrada, ContextBody{Alg: *alg, Quantum: *quantum, Aging: *aging}); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *caminhoHistorico != "" {
+		var err error
+		if historico, err = abrirHistorico(*caminhoHistorico, *maxHistorico); err != nil {
+			log.Fatalf("erro ao abrir o histórico %s: %v", *caminhoHistorico, err)
+		}
+	}
+
 	r:= gin.Default()
 
 	r.Use(cors.New(cors.Config{
 		AllowAllOrigins: true,
-		AllowMethods: []string{"POST"},
+		AllowMethods: []string{"GET", "POST", "DELETE"},
 		AllowHeaders: []string{"Origin", "Content-Type", "OPTIONS"},
 		ExposeHeaders: []string{"Content-Lenght"},
 		AllowCredentials: false,
 		MaxAge: 12 * time.Hour,
 	}))
 
+	// Só as simulações pedidas explicitamente vão para o histórico; exportações e prévias não
 	r.POST("/processes", func(c *gin.Context){
-		var body ContextBody 
+		var body ContextBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			log.Print("error: " + err.Error())
+			return
+		}
+
+		resultado, ok := simularCorpo(c, body)
+		if !ok {
+			return
+		}
+
+		registrarExecucao(body, &resultado)
+		c.JSON(200, resultado)
+	})
+
+	// Simulação a partir de um arquivo de carga enviado como multipart/form-data
+	// O arquivo vai no campo "arquivo" e os parâmetros nos campos alg, quantum, aging e trace
+	r.POST("/processes/upload", func(c *gin.Context){
+		body, err := lerFormularioUpload(c)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		resultado, ok := simularCorpo(c, body)
+		if !ok {
+			return
+		}
+
+		c.JSON(200, resultado)
+	})
+
+	// Exporta o resultado da simulação em outro formato (ex: /export/chrome)
+	r.POST("/export/:formato", func(c *gin.Context){
+		opcoes := OpcoesExportacao{
+			PorCPU: c.Query("cpu") == "true",
+			Tabela: c.Query("tabela"),
+		}
+
+		exportador, err := novoExportador(c.Param("formato"), opcoes)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		resultado, ok := simularRequisicao(c)
+		if !ok {
+			return
+		}
+
+		responderExportacao(c, exportador, resultado)
+	})
+
+	// Gráfico de Gantt em SVG, para incorporar em relatórios e slides
+	// O GET aceita os parâmetros na URL, ex: /gantt?alg=rr&quantum=2&input=0:5:2,0:2:3
+	r.GET("/gantt", func(c *gin.Context){
+		body, err := lerParametrosURL(c)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		resultado, ok := simularCorpo(c, body)
+		if !ok {
+			return
+		}
+
+		responderSVG(c, resultado)
+	})
+
+	r.POST("/gantt", func(c *gin.Context){
+		resultado, ok := simularRequisicao(c)
+		if !ok {
+			return
+		}
+
+		responderSVG(c, resultado)
+	})
+
+	// Simulações em segundo plano: POST /jobs enfileira e GET /jobs/:id acompanha o andamento
+	jobs := novoGerenciadorJobs(*workers, *filaJobs, 1000)
+
+	r.POST("/jobs", func(c *gin.Context){
+		var body JobBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		job, err := jobs.enfileirar(body)
+		if errors.Is(err, errFilaCheia) {
+			c.JSON(503, gin.H{"error": err.Error()})
+			return
+		} else if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Location", "/jobs/"+job.Id)
+		c.JSON(202, gin.H{"id": job.Id, "estado": job.Estado, "total": job.Total})
+	})
+
+	r.GET("/jobs/:id", func(c *gin.Context){
+		job, ok := jobs.buscar(c.Param("id"))
+		if !ok {
+			c.JSON(404, gin.H{"error": "job não encontrado"})
+			return
+		}
+
+		c.JSON(200, job)
+	})
+
+	// Histórico de execuções: cada simulação recebe um id que pode ser compartilhado
+	r.GET("/runs", func(c *gin.Context){
+		if !historicoAtivo(c) {
+			return
+		}
+
+		limite, err := strconv.Atoi(c.DefaultQuery("limite", "50"))
+		if err != nil || limite <= 0 {
+			c.JSON(400, gin.H{"error": "limite inválido"})
+			return
+		}
+
+		resumos, err := historico.listar(limite)
+		if err != nil {
+			c.JSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, resumos)
+	})
+
+	r.GET("/runs/:id", func(c *gin.Context){
+		if !historicoAtivo(c) {
+			return
+		}
+
+		execucao, err := historico.buscar(c.Param("id"))
+		if !responderErroHistorico(c, err) {
+			return
+		}
+		c.JSON(200, execucao)
+	})
+
+	r.DELETE("/runs/:id", func(c *gin.Context){
+		if !historicoAtivo(c) {
+			return
+		}
+
+		if !responderErroHistorico(c, historico.apagar(c.Param("id"))) {
+			return
+		}
+		c.Status(204)
+	})
 
-		// Desesserilizar JSON recebido no corpo da requisição
-		if err := c.ShouldBindJSON(&body); err!= nil{
+	// Compara duas execuções (do histórico ou simuladas na hora)
+	r.POST("/diff", func(c *gin.Context){
+		var body DiffBody
+		if err := c.ShouldBindJSON(&body); err != nil {
 			c.JSON(400, gin.H{"error": err.Error()})
-			log.Print("error: "  + err.Error())
 			return
 		}
 
-		fmt.Println(body.Input)
+		ctx, cancelar := limites.contextoComPrazo(c.Request.Context(), ContextBody{})
+		defer cancelar()
 
-		if body.Quantum <= 0 {
-			c.JSON(400, gin.H{"error": "Quantum deve ser maior que 0"})
+		a, err := body.A.carregar(ctx)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "a: " + err.Error()})
+			return
+		}
+		b, err := body.B.carregar(ctx)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "b: " + err.Error()})
+			return
+		}
+
+		c.JSON(200, compararExecucoes(a, b))
+	})
+
+	r.POST("/memory/paging", func(c *gin.Context){
+		var body PagingBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validarPaginacao(body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
 			return
 		}
 
-		for i, p := range body.Input {
+		ctx, cancelar := limites.contextoComPrazo(c.Request.Context(), ContextBody{TimeoutMs: body.TimeoutMs})
+		defer cancelar()
 
-			if p.Begin == 0 && p.Duration == 0 && p.Priority == 0{
-				c.JSON(400, gin.H{
-					"error": "Entrada inválida, por favor, tente novamente.",			
-				})
+		resultado, err := processPaging(ctx, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Printf("simulação cancelada: %v", ctx.Err())
+				c.JSON(503, gin.H{"error": err.Error()})
 				return
 			}
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, resultado)
+	})
 
-			if  p.Duration <= 0 {
-				c.JSON(400, gin.H{
-					"error":  fmt.Sprintf("Duração inválida no processo %d", i+1),
-				})
-				return
-			} else if p.Priority < 0 {
-				c.JSON(400, gin.H{
-					"error":  fmt.Sprintf("Prioridade inválida no processo %d", i+1),
-				})
-				return
-			}  else if p.Begin < 0 {
-				c.JSON(400, gin.H{
-					"error":  fmt.Sprintf("Tempo de início inválido no processo %d", i+1),
-				})
-				return
-			}	
+	r.POST("/memory/allocation", func(c *gin.Context){
+		var body AllocationBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validarAlocacao(body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
 		}
 
+		resultado, err := processAllocation(body)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, resultado)
+	})
+
+	// Algoritmo do banqueiro (segurança e pedido) e detecção de deadlock sobre matrizes de recursos
+	r.POST("/deadlock", func(c *gin.Context){
+		var body DeadlockBody
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		if err := validarDeadlock(body); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, processDeadlock(body))
+	})
+
+	// Escalonamento de disco: mesma linha do tempo e métricas de POST /processes, com o movimento do braço
+	r.POST("/disk", func(c *gin.Context){
+		resultado, ok := simularDisco(c)
+		if !ok {
+			return
+		}
+		c.JSON(200, resultado)
+	})
 
-		log.Printf("Algoritmo: %s, Quantum: %d, Aging: %d", body.Alg, body.Quantum, body.Aging)
+	// Exporta a simulação de disco nos mesmos formatos de /export/:formato
+	r.POST("/disk/export/:formato", func(c *gin.Context){
+		exportador, err := novoExportador(c.Param("formato"), OpcoesExportacao{Tabela: c.Query("tabela")})
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
 
-		tempoMedioVida, tempoMedioEspera,trocasContexto ,diagramaTempo, ordemProcessos:= processScheduler(body)
-		
-		c.JSON(200, gin.H{
-			"tempoMedioVida":tempoMedioVida, 
-			"tempoMedioEspera":tempoMedioEspera,
-			"trocasContexto": trocasContexto,
-			"diagramaTempo": diagramaTempo,
-			"ordemProcessos": ordemProcessos,
-		})
-	
+		resultado, ok := simularDisco(c)
+		if !ok {
+			return
+		}
+		responderExportacao(c, exportador, resultado.Resultado)
 	})
 
 	r.Run(":8081")
 }
+
+// lerParametrosURL monta o corpo da simulação a partir da query string
+// Os processos vêm em input separados por "," e cada um no formato "begin:duration:priority"
+func lerParametrosURL(c *gin.Context) (ContextBody, error) {
+	body := ContextBody{Alg: c.Query("alg")}
+
+	var err error
+	if body.Quantum, err = strconv.Atoi(c.DefaultQuery("quantum", "1")); err != nil {
+		return body, fmt.Errorf("Quantum inválido")
+	}
+	if body.Aging, err = strconv.Atoi(c.DefaultQuery("aging", "0")); err != nil {
+		return body, fmt.Errorf("Aging inválido")
+	}
+
+	for i, item := range strings.Split(c.Query("input"), ",") {
+		campos := strings.Fields(strings.ReplaceAll(item, ":", " "))
+		if len(campos) == 0 {
+			continue
+		}
+		if len(campos) != 3 {
+			return body, fmt.Errorf("Entrada inválida no processo %d", i+1)
+		}
+
+		valores := make([]int, 3)
+		for j, campo := range campos {
+			if valores[j], err = strconv.Atoi(campo); err != nil {
+				return body, fmt.Errorf("Entrada inválida no processo %d", i+1)
+			}
+		}
+		body.Input = append(body.Input, Processes{Begin: valores[0], Duration: valores[1], Priority: valores[2]})
+	}
+
+	return body, nil
+}
+
+// lerFormularioUpload monta o corpo da simulação a partir do formulário com o arquivo de carga
+// O formato vem do campo "formato" ou, se ausente, da extensão do arquivo
+// As demais opções do corpo JSON podem vir no campo "options", e os campos avulsos têm precedência
+func lerFormularioUpload(c *gin.Context) (ContextBody, error) {
+	var body ContextBody
+	if opcoes, ok := c.GetPostForm("options"); ok && opcoes != "" {
+		if err := json.Unmarshal([]byte(opcoes), &body); err != nil {
+			return body, fmt.Errorf("options inválido: %v", err)
+		}
+	}
+
+	if alg, ok := c.GetPostForm("alg"); ok {
+		body.Alg = alg
+	}
+	if trace, ok := c.GetPostForm("trace"); ok {
+		body.Trace = trace == "true"
+	}
+
+	var err error
+	if quantum, ok := c.GetPostForm("quantum"); ok {
+		if body.Quantum, err = strconv.Atoi(quantum); err != nil {
+			return body, fmt.Errorf("Quantum inválido")
+		}
+	} else if body.Quantum == 0 {
+		body.Quantum = 1
+	}
+	if aging, ok := c.GetPostForm("aging"); ok {
+		if body.Aging, err = strconv.Atoi(aging); err != nil {
+			return body, fmt.Errorf("Aging inválido")
+		}
+	}
+
+	cabecalho, err := c.FormFile("arquivo")
+	if err != nil {
+		return body, fmt.Errorf("arquivo de carga ausente no campo \"arquivo\"")
+	}
+
+	formato := c.PostForm("formato")
+	if formato == "" {
+		if formato, err = formatoPorExtensao(cabecalho.Filename); err != nil {
+			return body, err
+		}
+	}
+
+	arquivo, err := cabecalho.Open()
+	if err != nil {
+		return body, err
+	}
+	defer arquivo.Close()
+
+	if body.Input, err = lerCarga(formato, arquivo); err != nil {
+		return body, fmt.Errorf("%s: %v", cabecalho.Filename, err)
+	}
+
+	return body, nil
+}
+
+// simularArquivo executa a simulação com a carga lida de um arquivo e imprime o resultado
+func simularArquivo(caminho string, body ContextBody) error {
+	var err error
+	if body.Input, err = lerCargaArquivo(caminho); err != nil {
+		return fmt.Errorf("%s: %v", caminho, err)
+	}
+
+	if err := validarEntrada(body); err != nil {
+		return err
+	}
+
+	resultado, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		return err
+	}
+
+	saida, err := json.MarshalIndent(resultado, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(saida))
+	return nil
+}
+
+// simularRequisicao lê o corpo JSON, valida as entradas e executa a simulação
+// Em caso de erro a resposta já é escrita e ok é falso
+func simularRequisicao(c *gin.Context) (Resultado, bool) {
+	var body ContextBody
+
+	// Desesserilizar JSON recebido no corpo da requisição
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		log.Print("error: " + err.Error())
+		return Resultado{}, false
+	}
+
+	return simularCorpo(c, body)
+}
+
+// simularCorpo valida as entradas já lidas e executa a simulação
+func simularCorpo(c *gin.Context, body ContextBody) (Resultado, bool) {
+	if err := validarEntrada(body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return Resultado{}, false
+	}
+
+	log.Printf("Algoritmo: %s, Quantum: %d, Aging: %d", body.Alg, body.Quantum, body.Aging)
+
+	// A simulação é cancelada se o cliente desconectar ou o tempo limite acabar
+	ctx, cancelar := limites.contextoComPrazo(c.Request.Context(), body)
+	defer cancelar()
+
+	resultado, err := processScheduler(ctx, body, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Printf("simulação cancelada: %v", ctx.Err())
+			c.JSON(503, gin.H{"error": err.Error()})
+			return Resultado{}, false
+		}
+		c.JSON(400, gin.H{"error": err.Error()})
+		return Resultado{}, false
+	}
+
+	return resultado, true
+}
+
+// simularDisco lê, valida e executa a simulação de escalonamento de disco
+func simularDisco(c *gin.Context) (ResultadoDisco, bool) {
+	var body DiskBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return ResultadoDisco{}, false
+	}
+	if err := validarDisco(body); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return ResultadoDisco{}, false
+	}
+
+	ctx, cancelar := limites.contextoComPrazo(c.Request.Context(), ContextBody{TimeoutMs: body.TimeoutMs})
+	defer cancelar()
+
+	resultado, err := processDisk(ctx, body)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Printf("simulação cancelada: %v", ctx.Err())
+			c.JSON(503, gin.H{"error": err.Error()})
+			return ResultadoDisco{}, false
+		}
+		c.JSON(400, gin.H{"error": err.Error()})
+		return ResultadoDisco{}, false
+	}
+	return resultado, true
+}
+
+// validarEntrada verifica o quantum e os dados de cada processo recebido
+func validarEntrada(body ContextBody) error {
+	if body.Quantum <= 0 {
+		return fmt.Errorf("Quantum deve ser maior que 0")
+	}
+	if body.StarvationThreshold < 0 {
+		return fmt.Errorf("starvationThreshold não pode ser negativo")
+	}
+	if body.ThroughputWindow < 0 {
+		return fmt.Errorf("throughputWindow não pode ser negativo")
+	}
+	if body.Rounding != nil && *body.Rounding > 15 {
+		return fmt.Errorf("rounding deve ter no máximo 15 casas decimais")
+	}
+	if err := limites.validar(body); err != nil {
+		return err
+	}
+	if err := validarMemoria(body); err != nil {
+		return err
+	}
+
+	body, err := aplicarCenario(body)
+	if err != nil {
+		return err
+	}
+	if err := validarProcessos(body.Input); err != nil {
+		return err
+	}
+	if err := validarProgramas(body); err != nil {
+		return err
+	}
+	if err := validarThreads(body); err != nil {
+		return err
+	}
+	return validarHierarquia(body)
+}
+
+// validarProcessos verifica os dados de cada processo da carga
+func validarProcessos(input []Processes) error {
+	ids := map[string]int{}
+	for i, p := range input {
+
+		// O id é usado para juntar os resultados com o catálogo, então não pode repetir
+		if p.Id != "" {
+			if anterior, ok := ids[p.Id]; ok {
+				return fmt.Errorf("Id %q repetido nos processos %d e %d", p.Id, anterior, i+1)
+			}
+			ids[p.Id] = i + 1
+		}
+
+		duracao := duracaoDaEntrada(p)
+		if p.Begin == 0 && duracao == 0 && p.Priority == 0 {
+			return fmt.Errorf("Entrada inválida, por favor, tente novamente.")
+		}
+
+		if duracao <= 0 {
+			return fmt.Errorf("Duração inválida no processo %d", i+1)
+		} else if p.Priority < 0 {
+			return fmt.Errorf("Prioridade inválida no processo %d", i+1)
+		} else if p.Begin < 0 {
+			return fmt.Errorf("Tempo de início inválido no processo %d", i+1)
+		} else if p.Memory < 0 {
+			return fmt.Errorf("Memória inválida no processo %d", i+1)
+		} else if err := validarSecoesCriticas(p); err != nil {
+			return fmt.Errorf("Processo %d: %v", i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// responderExportacao escreve o resultado convertido pelo exportador como um arquivo para download
+func responderExportacao(c *gin.Context, exportador Exportador, resultado Resultado) {
+	conteudo, err := exportador.exportar(resultado)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"escalonamento.%s\"", exportador.extensao()))
+	c.Data(200, exportador.tipoConteudo(), conteudo)
+}
+
+// responderSVG devolve o gráfico de Gantt para ser exibido diretamente (sem download)
+func responderSVG(c *gin.Context, resultado Resultado) {
+	exportador := &SVGGantt{}
+	conteudo, err := exportador.exportar(resultado)
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(200, exportador.tipoConteudo(), conteudo)
+}
+
+// historicoAtivo responde 404 quando o servidor foi iniciado sem histórico
+func historicoAtivo(c *gin.Context) bool {
+	if historico == nil {
+		c.JSON(404, gin.H{"error": "o histórico de execuções está desativado neste servidor"})
+		return false
+	}
+	return true
+}
+
+// responderErroHistorico escreve a resposta de erro, se houver, e indica se a operação deu certo
+func responderErroHistorico(c *gin.Context, err error) bool {
+	if errors.Is(err, errExecucaoNaoEncontrada) {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return false
+	} else if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// DeadlockBody é o corpo de POST /deadlock
+// As matrizes têm uma linha por processo e uma coluna por recurso
+type DeadlockBody struct {
+	Alg            string   `json:"alg"`            // "safety", "request" ou "detection"
+	Resources      []string `json:"resources"`      // Nomes dos recursos (padrão R1, R2, ...)
+	Processes      []string `json:"processes"`      // Nomes dos processos (padrão P1, P2, ...)
+	Available      []int    `json:"available"`      // Instâncias livres de cada recurso
+	Allocation     [][]int  `json:"allocation"`     // Instâncias que cada processo tem
+	Max            [][]int  `json:"max"`            // Necessidade máxima declarada (safety e request)
+	Request        [][]int  `json:"request"`        // Pedidos pendentes de cada processo (detection)
+	Process        int      `json:"process"`        // Processo que faz o pedido, a partir de 1 (request)
+	ProcessRequest []int    `json:"processRequest"` // Instâncias pedidas pelo processo (request)
+}
+
+// PassoTermino é um passo da simulação de término: o processo cabe no trabalho disponível,
+// termina e devolve o que tinha alocado
+type PassoTermino struct {
+	Processo string `json:"processo"`
+	Trabalho []int  `json:"trabalho"` // Instâncias disponíveis antes do passo
+	Pedido   []int  `json:"pedido"`   // Necessidade (banqueiro) ou pedido pendente (detecção)
+	Liberado []int  `json:"liberado"` // Alocação devolvida ao terminar
+}
+
+// ArestaEspera é uma aresta do grafo de espera: De espera instâncias que Para tem
+type ArestaEspera struct {
+	De       string   `json:"de"`
+	Para     string   `json:"para"`
+	Recursos []string `json:"recursos"`
+}
+
+// ResultadoDeadlock é o resultado de POST /deadlock
+type ResultadoDeadlock struct {
+	Algoritmo   string   `json:"algoritmo"`
+	Recursos    []string `json:"recursos"`
+	Processos   []string `json:"processos"`
+	Disponivel  []int    `json:"disponivel"`
+	Alocacao    [][]int  `json:"alocacao"`
+	Necessidade [][]int  `json:"necessidade,omitempty"` // Max - Alocacao (safety e request)
+
+	// Pedido (request): se foi concedido e por quê; concedido, o estado acima já inclui o pedido
+	Concedido *bool  `json:"concedido,omitempty"`
+	Motivo    string `json:"motivo,omitempty"`
+
+	Seguro      *bool          `json:"seguro,omitempty"`      // safety e request
+	Deadlock    *bool          `json:"deadlock,omitempty"`    // detection
+	Sequencia   []string       `json:"sequencia"`             // Ordem em que os processos conseguem terminar
+	Passos      []PassoTermino `json:"passos"`                // Cada passo da sequência
+	Restantes   []string       `json:"restantes"`             // Processos que não conseguem terminar (em deadlock, na detecção)
+	GrafoEspera []ArestaEspera `json:"grafoEspera,omitempty"` // Entre os processos em deadlock (detection)
+	SemAlocacao []string       `json:"semAlocacao,omitempty"` // Processos sem nada alocado, que não podem causar deadlock (detection)
+}
+
+// Limites do módulo de deadlock
+const maxRecursosDeadlock = 64
+
+// sequenciaDeTermino procura uma ordem em que todos os processos consigam terminar: a cada passagem pela
+// lista, todo processo cujo pedido cabe no trabalho disponível termina e devolve a sua alocação
+// Devolve a ordem de término, o trabalho disponível antes de cada término e os processos que sobraram
+// Os processos marcados em terminados ficam fora da sequência
+func sequenciaDeTermino(disponivel []int, alocacao, pedido [][]int, terminados []bool) ([]int, [][]int, []int) {
+	trabalho := slices.Clone(disponivel)
+	terminou := slices.Clone(terminados)
+
+	var ordem []int
+	var trabalhos [][]int
+	for progresso := true; progresso; {
+		progresso = false
+		for i := range alocacao {
+			if terminou[i] || !cabe(pedido[i], trabalho) {
+				continue
+			}
+			ordem = append(ordem, i)
+			trabalhos = append(trabalhos, slices.Clone(trabalho))
+			for k := range trabalho {
+				trabalho[k] += alocacao[i][k]
+			}
+			terminou[i] = true
+			progresso = true
+		}
+	}
+
+	var restantes []int
+	for i, t := range terminou {
+		if !t {
+			restantes = append(restantes, i)
+		}
+	}
+	return ordem, trabalhos, restantes
+}
+
+// detectarDeadlock aplica o algoritmo de detecção: processos sem nada alocado não podem segurar
+// ninguém e começam como terminados; os que sobram ao fim estão em deadlock
+func detectarDeadlock(disponivel []int, alocacao, pedido [][]int) ([]int, [][]int, []int) {
+	terminados := make([]bool, len(alocacao))
+	for i, linha := range alocacao {
+		terminados[i] = !slices.ContainsFunc(linha, func(x int) bool { return x > 0 })
+	}
+	return sequenciaDeTermino(disponivel, alocacao, pedido, terminados)
+}
+
+// grafoDeEspera monta o grafo de espera entre os processos informados: i espera j
+// se i pede instâncias de um recurso que j tem alocado
+func grafoDeEspera(processos []int, alocacao, pedido [][]int, nomesProcessos, nomesRecursos []string) []ArestaEspera {
+	arestas := []ArestaEspera{}
+	for _, i := range processos {
+		for _, j := range processos {
+			if i == j {
+				continue
+			}
+			var recursos []string
+			for k := range pedido[i] {
+				if pedido[i][k] > 0 && alocacao[j][k] > 0 {
+					recursos = append(recursos, nomesRecursos[k])
+				}
+			}
+			if len(recursos) > 0 {
+				arestas = append(arestas, ArestaEspera{De: nomesProcessos[i], Para: nomesProcessos[j], Recursos: recursos})
+			}
+		}
+	}
+	return arestas
+}
+
+// cabe indica se o pedido cabe nas instâncias disponíveis, recurso a recurso
+func cabe(pedido, disponivel []int) bool {
+	for k := range pedido {
+		if pedido[k] > disponivel[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// validarDeadlock confere as dimensões e os valores das matrizes
+func validarDeadlock(body DeadlockBody) error {
+	switch body.Alg {
+	case "safety", "request", "detection":
+	default:
+		return fmt.Errorf("algoritmo inválido: use \"safety\", \"request\" ou \"detection\"")
+	}
+
+	n, m := len(body.Allocation), len(body.Available)
+	if n == 0 || m == 0 {
+		return fmt.Errorf("available e allocation são obrigatórios")
+	}
+	if n > limites.MaxProcessos {
+		return fmt.Errorf("a matriz tem %d processos; o limite do servidor é %d", n, limites.MaxProcessos)
+	}
+	if m > maxRecursosDeadlock {
+		return fmt.Errorf("a matriz tem %d recursos; o limite é %d", m, maxRecursosDeadlock)
+	}
+	if body.Resources != nil && len(body.Resources) != m {
+		return fmt.Errorf("resources deve ter %d nomes, um por recurso", m)
+	}
+	if body.Processes != nil && len(body.Processes) != n {
+		return fmt.Errorf("processes deve ter %d nomes, um por processo", n)
+	}
+	if err := validarVetor("available", body.Available, m); err != nil {
+		return err
+	}
+	if err := validarMatriz("allocation", body.Allocation, n, m); err != nil {
+		return err
+	}
+
+	if body.Alg == "detection" {
+		return validarMatriz("request", body.Request, n, m)
+	}
+
+	if err := validarMatriz("max", body.Max, n, m); err != nil {
+		return err
+	}
+	for i := range n {
+		for k := range m {
+			if body.Allocation[i][k] > body.Max[i][k] {
+				return fmt.Errorf("o processo %d tem mais instâncias do recurso %d do que declarou em max", i+1, k+1)
+			}
+		}
+	}
+	if body.Alg == "request" {
+		if body.Process < 1 || body.Process > n {
+			return fmt.Errorf("process deve estar entre 1 e %d", n)
+		}
+		return validarVetor("processRequest", body.ProcessRequest, m)
+	}
+	return nil
+}
+
+// validarMatriz confere se a matriz tem n linhas de m valores não negativos
+func validarMatriz(nome string, matriz [][]int, n, m int) error {
+	if len(matriz) != n {
+		return fmt.Errorf("%s deve ter %d linhas, uma por processo", nome, n)
+	}
+	for i, linha := range matriz {
+		if err := validarVetor(fmt.Sprintf("%s[%d]", nome, i+1), linha, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validarVetor confere se o vetor tem m valores não negativos
+func validarVetor(nome string, vetor []int, m int) error {
+	if len(vetor) != m {
+		return fmt.Errorf("%s deve ter %d valores, um por recurso", nome, m)
+	}
+	for _, x := range vetor {
+		if x < 0 {
+			return fmt.Errorf("%s não pode ter valores negativos", nome)
+		}
+	}
+	return nil
+}
+
+// nomesPadrao devolve os nomes informados ou prefixo1, prefixo2, ...
+func nomesPadrao(nomes []string, n int, prefixo string) []string {
+	if nomes != nil {
+		return nomes
+	}
+	nomes = make([]string, n)
+	for i := range nomes {
+		nomes[i] = fmt.Sprintf("%s%d", prefixo, i+1)
+	}
+	return nomes
+}
+
+// processDeadlock executa o algoritmo do banqueiro (segurança ou pedido) ou a detecção de deadlock
+func processDeadlock(body DeadlockBody) ResultadoDeadlock {
+	n, m := len(body.Allocation), len(body.Available)
+	r := ResultadoDeadlock{
+		Algoritmo:  body.Alg,
+		Recursos:   nomesPadrao(body.Resources, m, "R"),
+		Processos:  nomesPadrao(body.Processes, n, "P"),
+		Disponivel: slices.Clone(body.Available),
+		Alocacao:   make([][]int, n),
+		Sequencia:  []string{},
+		Passos:     []PassoTermino{},
+		Restantes:  []string{},
+	}
+	for i := range n {
+		r.Alocacao[i] = slices.Clone(body.Allocation[i])
+	}
+
+	if body.Alg == "detection" {
+		ordem, trabalhos, restantes := detectarDeadlock(r.Disponivel, r.Alocacao, body.Request)
+		r.preencherSequencia(ordem, trabalhos, restantes, body.Request)
+		deadlock := len(restantes) > 0
+		r.Deadlock = &deadlock
+		if deadlock {
+			r.GrafoEspera = grafoDeEspera(restantes, r.Alocacao, body.Request, r.Processos, r.Recursos)
+		}
+		for i, linha := range r.Alocacao {
+			if !slices.ContainsFunc(linha, func(x int) bool { return x > 0 }) {
+				r.SemAlocacao = append(r.SemAlocacao, r.Processos[i])
+			}
+		}
+		return r
+	}
+
+	r.Necessidade = make([][]int, n)
+	for i := range n {
+		r.Necessidade[i] = make([]int, m)
+		for k := range m {
+			r.Necessidade[i][k] = body.Max[i][k] - r.Alocacao[i][k]
+		}
+	}
+
+	// Pedido: só é concedido se couber na necessidade declarada, nos recursos livres
+	// e se o estado resultante continuar seguro
+	if body.Alg == "request" {
+		i := body.Process - 1
+		pedido := body.ProcessRequest
+		concedido := false
+		r.Concedido = &concedido
+
+		switch {
+		case !cabe(pedido, r.Necessidade[i]):
+			r.Motivo = "o pedido passa da necessidade máxima declarada pelo processo"
+			return r
+		case !cabe(pedido, r.Disponivel):
+			r.Motivo = "não há instâncias livres suficientes; o processo espera"
+			return r
+		}
+
+		disponivel, alocacao, necessidade := slices.Clone(r.Disponivel), slices.Clone(r.Alocacao[i]), slices.Clone(r.Necessidade[i])
+		for k := range m {
+			r.Disponivel[k] -= pedido[k]
+			r.Alocacao[i][k] += pedido[k]
+			r.Necessidade[i][k] -= pedido[k]
+		}
+		ordem, trabalhos, restantes := sequenciaDeTermino(r.Disponivel, r.Alocacao, r.Necessidade, make([]bool, n))
+		if len(restantes) > 0 {
+			// O estado com o pedido seria inseguro: desfaz a alocação e mostra por que
+			r.Motivo = "o estado resultante seria inseguro; o processo espera"
+			r.preencherSequencia(ordem, trabalhos, restantes, r.Necessidade)
+			seguro := false
+			r.Seguro = &seguro
+			r.Disponivel, r.Alocacao[i], r.Necessidade[i] = disponivel, alocacao, necessidade
+			return r
+		}
+		concedido = true
+		r.Motivo = "o estado resultante é seguro"
+	}
+
+	ordem, trabalhos, restantes := sequenciaDeTermino(r.Disponivel, r.Alocacao, r.Necessidade, make([]bool, n))
+	r.preencherSequencia(ordem, trabalhos, restantes, r.Necessidade)
+	seguro := len(restantes) == 0
+	r.Seguro = &seguro
+	return r
+}
+
+// preencherSequencia converte os índices da simulação de término em nomes e passos
+func (r *ResultadoDeadlock) preencherSequencia(ordem []int, trabalhos [][]int, restantes []int, pedido [][]int) {
+	for passo, i := range ordem {
+		r.Sequencia = append(r.Sequencia, r.Processos[i])
+		r.Passos = append(r.Passos, PassoTermino{
+			Processo: r.Processos[i],
+			Trabalho: trabalhos[passo],
+			Pedido:   pedido[i],
+			Liberado: r.Alocacao[i],
+		})
+	}
+	for _, i := range restantes {
+		r.Restantes = append(r.Restantes, r.Processos[i])
+	}
+}
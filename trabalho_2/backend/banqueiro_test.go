@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// estadoSilberschatz é o exemplo do banqueiro do livro do Silberschatz: 5 processos e recursos A, B e C
+func estadoSilberschatz(alg string) DeadlockBody {
+	return DeadlockBody{
+		Alg:        alg,
+		Available:  []int{3, 3, 2},
+		Allocation: [][]int{{0, 1, 0}, {2, 0, 0}, {3, 0, 2}, {2, 1, 1}, {0, 0, 2}},
+		Max:        [][]int{{7, 5, 3}, {3, 2, 2}, {9, 0, 2}, {2, 2, 2}, {4, 3, 3}},
+	}
+}
+
+func TestSeguranca(t *testing.T) {
+	casos := []struct {
+		nome      string
+		body      DeadlockBody
+		seguro    bool
+		sequencia []string
+		restantes []string
+	}{
+		{
+			nome:      "silberschatz",
+			body:      estadoSilberschatz("safety"),
+			seguro:    true,
+			sequencia: []string{"P2", "P4", "P5", "P1", "P3"},
+			restantes: []string{},
+		},
+		{
+			// 12 unidades de fita: com 3 livres todos terminam
+			nome:      "fitas seguro",
+			body:      DeadlockBody{Alg: "safety", Available: []int{3}, Allocation: [][]int{{5}, {2}, {2}}, Max: [][]int{{10}, {4}, {9}}},
+			seguro:    true,
+			sequencia: []string{"P2", "P1", "P3"},
+			restantes: []string{},
+		},
+		{
+			// O mesmo estado depois de entregar mais uma unidade a P3: só P2 consegue terminar
+			nome:      "fitas inseguro",
+			body:      DeadlockBody{Alg: "safety", Available: []int{2}, Allocation: [][]int{{5}, {2}, {3}}, Max: [][]int{{10}, {4}, {9}}},
+			seguro:    false,
+			sequencia: []string{"P2"},
+			restantes: []string{"P1", "P3"},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if err := validarDeadlock(c.body); err != nil {
+				t.Fatal(err)
+			}
+			r := processDeadlock(c.body)
+			if r.Seguro == nil || *r.Seguro != c.seguro {
+				t.Errorf("seguro = %v, esperado %v", r.Seguro, c.seguro)
+			}
+			if !slices.Equal(r.Sequencia, c.sequencia) {
+				t.Errorf("sequência %v, esperado %v", r.Sequencia, c.sequencia)
+			}
+			if !slices.Equal(r.Restantes, c.restantes) {
+				t.Errorf("restantes %v, esperado %v", r.Restantes, c.restantes)
+			}
+		})
+	}
+}
+
+func TestPedidoBanqueiro(t *testing.T) {
+	// P2 pede (1, 0, 2): o estado continua seguro e o pedido é concedido
+	body := estadoSilberschatz("request")
+	body.Process, body.ProcessRequest = 2, []int{1, 0, 2}
+	r := processDeadlock(body)
+	if r.Concedido == nil || !*r.Concedido {
+		t.Fatalf("pedido de P2 recusado: %s", r.Motivo)
+	}
+	if !slices.Equal(r.Disponivel, []int{2, 3, 0}) || !slices.Equal(r.Alocacao[1], []int{3, 0, 2}) || !slices.Equal(r.Necessidade[1], []int{0, 2, 0}) {
+		t.Errorf("estado após o pedido: disponível %v, alocação %v, necessidade %v", r.Disponivel, r.Alocacao[1], r.Necessidade[1])
+	}
+	if !slices.Equal(r.Sequencia, []string{"P2", "P4", "P5", "P1", "P3"}) {
+		t.Errorf("sequência %v", r.Sequencia)
+	}
+
+	// Depois dele, P1 pede (0, 2, 0): há instâncias livres, mas o estado ficaria inseguro
+	body.Available = r.Disponivel
+	body.Allocation = r.Alocacao
+	body.Process, body.ProcessRequest = 1, []int{0, 2, 0}
+	r = processDeadlock(body)
+	if r.Concedido == nil || *r.Concedido {
+		t.Fatal("o pedido de P1 deveria ser recusado")
+	}
+	if r.Seguro == nil || *r.Seguro || len(r.Restantes) == 0 {
+		t.Errorf("o estado com o pedido deveria ser inseguro: seguro %v, restantes %v", r.Seguro, r.Restantes)
+	}
+	if !slices.Equal(r.Disponivel, []int{2, 3, 0}) || !slices.Equal(r.Alocacao[0], []int{0, 1, 0}) {
+		t.Errorf("o pedido recusado não foi desfeito: disponível %v, alocação %v", r.Disponivel, r.Alocacao[0])
+	}
+
+	// Um pedido maior que as instâncias livres espera sem nem testar a segurança
+	body.Process, body.ProcessRequest = 5, []int{3, 3, 0}
+	if r = processDeadlock(body); r.Concedido == nil || *r.Concedido || r.Seguro != nil {
+		t.Errorf("o pedido de P5 deveria esperar por instâncias livres: %+v", r)
+	}
+}
+
+func TestDetectarDeadlock(t *testing.T) {
+	disponivel := []int{0, 0, 0}
+	alocacao := [][]int{{0, 1, 0}, {2, 0, 0}, {3, 0, 3}, {2, 1, 1}, {0, 0, 2}, {0, 0, 0}}
+	pedido := [][]int{{0, 0, 0}, {2, 0, 2}, {0, 0, 0}, {1, 0, 0}, {0, 0, 2}, {5, 5, 5}}
+
+	// O último processo não tem nada alocado: o pedido dele nunca cabe, mas ele não segura ninguém
+	ordem, _, restantes := detectarDeadlock(disponivel, alocacao, pedido)
+	if !slices.Equal(ordem, []int{0, 2, 3, 4, 1}) || len(restantes) != 0 {
+		t.Errorf("ordem %v e restantes %v, esperado todos terminando sem deadlock", ordem, restantes)
+	}
+
+	// Sem a exclusão, a simulação de término acusaria o processo sem alocação
+	if _, _, restantes := sequenciaDeTermino(disponivel, alocacao, pedido, make([]bool, len(alocacao))); !slices.Equal(restantes, []int{5}) {
+		t.Errorf("restantes sem a exclusão %v, esperado [5]", restantes)
+	}
+
+	// Com mais um pedido de C por P3, só P1 consegue terminar
+	pedido[2] = []int{0, 0, 1}
+	r := processDeadlock(DeadlockBody{Alg: "detection", Available: disponivel, Allocation: alocacao, Request: pedido})
+	if r.Deadlock == nil || !*r.Deadlock {
+		t.Fatal("deadlock não detectado")
+	}
+	if !slices.Equal(r.Sequencia, []string{"P1"}) || !slices.Equal(r.Restantes, []string{"P2", "P3", "P4", "P5"}) {
+		t.Errorf("sequência %v e restantes %v", r.Sequencia, r.Restantes)
+	}
+	if !slices.Equal(r.SemAlocacao, []string{"P6"}) {
+		t.Errorf("sem alocação %v, esperado [P6]", r.SemAlocacao)
+	}
+	for _, aresta := range r.GrafoEspera {
+		if aresta.De == "P6" || aresta.Para == "P6" {
+			t.Errorf("o processo sem alocação não deveria estar no grafo de espera: %+v", aresta)
+		}
+	}
+}
+
+// deadlockCruzado é o deadlock de dois processos que pegam r1 e r2 em ordens opostas
+func deadlockCruzado(recuperacao string) ContextBody {
+	return ContextBody{Alg: "rr", Quantum: 1, DeadlockRecovery: recuperacao, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 2, Name: "A", CriticalSections: []CriticalSection{
+			{Resource: "r1", Start: 0, Duration: 3}, {Resource: "r2", Start: 1, Duration: 2}}},
+		{Begin: 0, Duration: 3, Priority: 1, Name: "B", CriticalSections: []CriticalSection{
+			{Resource: "r2", Start: 0, Duration: 3}, {Resource: "r1", Start: 1, Duration: 2}}},
+	}}
+}
+
+func TestRecuperacaoDeadlock(t *testing.T) {
+	casos := []struct {
+		recuperacao  string
+		interrompido bool
+		vitima       string
+		concluidos   []bool
+		terminos     map[string]int
+	}{
+		{recuperacaoNenhuma, true, "", []bool{false, false}, map[string]int{"A": -1, "B": -1}},
+		{recuperacaoAbortar, false, "B", []bool{true, false}, map[string]int{"A": 5, "B": -1}},
+		{recuperacaoReinicio, false, "B", []bool{true, true}, map[string]int{"A": 5, "B": 8}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.recuperacao, func(t *testing.T) {
+			r, err := processScheduler(context.Background(), deadlockCruzado(c.recuperacao), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Interrompido != c.interrompido {
+				t.Errorf("interrompido = %v, esperado %v", r.Interrompido, c.interrompido)
+			}
+			deadlocks := r.Recursos.Deadlocks
+			if len(deadlocks) != 1 || deadlocks[0].Instante != 3 || !slices.Equal(deadlocks[0].Processos, []string{"A", "B"}) {
+				t.Fatalf("deadlocks %+v, esperado um só entre A e B no instante 3", deadlocks)
+			}
+			if deadlocks[0].Vitima != c.vitima {
+				t.Errorf("vítima %q, esperado %q", deadlocks[0].Vitima, c.vitima)
+			}
+			for i, p := range r.Processos {
+				if p.Concluido != c.concluidos[i] {
+					t.Errorf("%s concluído = %v, esperado %v", p.Nome, p.Concluido, c.concluidos[i])
+				}
+			}
+			for nome, fim := range c.terminos {
+				if termino(r)[nome] != fim {
+					t.Errorf("%s terminou em %d, esperado %d", nome, termino(r)[nome], fim)
+				}
+			}
+
+			// B executou no instante 1, antes do deadlock; o rollback não apaga o início
+			if b := r.Processos[1]; b.Inicio != 1 {
+				t.Errorf("B começou em %d, esperado 1", b.Inicio)
+			}
+		})
+	}
+}
+
+func TestTrabalhoPerdidoNoReinicio(t *testing.T) {
+	r, err := processScheduler(context.Background(), deadlockCruzado(recuperacaoReinicio), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// B executou 1 unidade antes do deadlock e a refez depois do reinício: essa unidade
+	// foi CPU desperdiçada, não espera
+	b := r.Processos[1]
+	if b.Reinicios != 1 || b.TrabalhoPerdido != 1 {
+		t.Errorf("B com %d reinícios e %d de trabalho perdido, esperado 1 e 1", b.Reinicios, b.TrabalhoPerdido)
+	}
+	if b.TempoVida != 8 || b.TempoEspera != 4 {
+		t.Errorf("B com tempo de vida %d e espera %d, esperado 8 e 4", b.TempoVida, b.TempoEspera)
+	}
+	if b.TempoVida != b.TempoEspera+b.Duracao+b.TrabalhoPerdido {
+		t.Errorf("tempo de vida %d diferente de espera %d + duração %d + trabalho perdido %d", b.TempoVida, b.TempoEspera, b.Duracao, b.TrabalhoPerdido)
+	}
+
+	// A nunca foi reiniciado
+	if a := r.Processos[0]; a.Reinicios != 0 || a.TrabalhoPerdido != 0 {
+		t.Errorf("A com %d reinícios e %d de trabalho perdido, esperado nenhum", a.Reinicios, a.TrabalhoPerdido)
+	}
+}
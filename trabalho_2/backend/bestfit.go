@@ -0,0 +1,25 @@
+package main
+
+// BestFit usa a menor lacuna em que a requisição cabe, deixando a menor sobra possível
+// Empates ficam com a lacuna de menor endereço
+type BestFit struct {
+	s *SimuladorAlocacao
+}
+
+func (alg *BestFit) alocar(tamanho int) int {
+	escolhida := -1
+	for i, seg := range alg.s.segmentos {
+		if seg.livre() && seg.tamanho >= tamanho &&
+			(escolhida == -1 || seg.tamanho < alg.s.segmentos[escolhida].tamanho) {
+			escolhida = i
+		}
+	}
+	if escolhida == -1 {
+		return -1
+	}
+	return alg.s.dividir(escolhida, tamanho)
+}
+
+func (alg *BestFit) liberar(i int) {
+	alg.s.juntarVizinhos(i)
+}
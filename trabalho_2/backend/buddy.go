@@ -0,0 +1,56 @@
+package main
+
+// Buddy arredonda cada requisição para uma potência de dois e divide blocos ao meio até chegar
+// a esse tamanho; ao liberar, o bloco só se junta ao seu companheiro (buddy) da divisão
+// A diferença entre o bloco e o que o processo pediu é a fragmentação interna
+type Buddy struct {
+	s        *SimuladorAlocacao
+	minBloco int // Menor bloco que pode ser alocado
+}
+
+func (alg *Buddy) alocar(tamanho int) int {
+	bloco := alg.minBloco
+	for bloco < tamanho {
+		bloco *= 2
+	}
+
+	// Menor bloco livre que comporta a requisição; empates ficam com o menor endereço
+	escolhido := -1
+	for i, seg := range alg.s.segmentos {
+		if seg.livre() && seg.tamanho >= bloco &&
+			(escolhido == -1 || seg.tamanho < alg.s.segmentos[escolhido].tamanho) {
+			escolhido = i
+		}
+	}
+	if escolhido == -1 {
+		return -1
+	}
+
+	// A metade de cima de cada divisão fica livre como companheira
+	for alg.s.segmentos[escolhido].tamanho > bloco {
+		alg.s.dividir(escolhido, alg.s.segmentos[escolhido].tamanho/2)
+	}
+	return escolhido
+}
+
+func (alg *Buddy) liberar(i int) {
+	for {
+		seg := alg.s.segmentos[i]
+
+		// O companheiro fica no endereço com o bit do tamanho do bloco invertido
+		companheiro := seg.inicio ^ seg.tamanho
+		j := i + 1
+		if companheiro < seg.inicio {
+			j = i - 1
+		}
+		if j < 0 || j >= len(alg.s.segmentos) {
+			return
+		}
+
+		vizinho := alg.s.segmentos[j]
+		if !vizinho.livre() || vizinho.inicio != companheiro || vizinho.tamanho != seg.tamanho {
+			return
+		}
+		i = alg.s.juntar(min(i, j))
+	}
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Formatos de carga de trabalho aceitos por lerCarga
+const (
+	formatoTexto = "txt"  // "begin duration priority [nome]" por linha, como em processos.txt
+	formatoCSV   = "csv"  // CSV com cabeçalho
+	formatoJSON  = "json" // Lista de processos ou objeto com o campo "input"
+	formatoYAML  = "yaml" // Mesma estrutura do JSON
+)
+
+// formatoPorExtensao descobre o formato da carga pela extensão do arquivo
+func formatoPorExtensao(nome string) (string, error) {
+	switch strings.ToLower(filepath.Ext(nome)) {
+	case ".txt", "":
+		return formatoTexto, nil
+	case ".csv":
+		return formatoCSV, nil
+	case ".json":
+		return formatoJSON, nil
+	case ".yaml", ".yml":
+		return formatoYAML, nil
+	default:
+		return "", fmt.Errorf("formato de carga desconhecido para o arquivo %s", nome)
+	}
+}
+
+// lerCargaArquivo lê a carga de trabalho de um arquivo, escolhendo o formato pela extensão
+func lerCargaArquivo(caminho string) ([]Processes, error) {
+	formato, err := formatoPorExtensao(caminho)
+	if err != nil {
+		return nil, err
+	}
+
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return nil, err
+	}
+	defer arquivo.Close()
+
+	return lerCarga(formato, arquivo)
+}
+
+// lerCarga interpreta uma carga de trabalho no formato indicado
+// Os erros trazem o número da linha em que o problema foi encontrado
+func lerCarga(formato string, r io.Reader) ([]Processes, error) {
+	var processos []Processes
+	var linhas []int // Linha de cada processo, para as mensagens de validação
+	var err error
+
+	switch formato {
+	case formatoTexto:
+		processos, linhas, err = lerCargaTexto(r)
+	case formatoCSV:
+		processos, linhas, err = lerCargaCSV(r)
+	case formatoJSON:
+		processos, linhas, err = lerCargaJSON(r)
+	case formatoYAML:
+		processos, linhas, err = lerCargaYAML(r)
+	default:
+		return nil, fmt.Errorf("formato de carga inválido: %s", formato)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(processos) == 0 {
+		return nil, fmt.Errorf("arquivo vazio")
+	}
+
+	for i, p := range processos {
+		if err := validarProcesso(p); err != nil {
+			return nil, fmt.Errorf("linha %d: %v", linhas[i], err)
+		}
+	}
+
+	return processos, nil
+}
+
+// validarProcesso confere os valores de um processo lido da carga
+func validarProcesso(p Processes) error {
+	if p.Duration <= 0 {
+		return fmt.Errorf("duração inválida (%d)", p.Duration)
+	} else if p.Priority < 0 {
+		return fmt.Errorf("prioridade inválida (%d)", p.Priority)
+	} else if p.Begin < 0 {
+		return fmt.Errorf("tempo de início inválido (%d)", p.Begin)
+	} else if p.Memory < 0 {
+		return fmt.Errorf("memória inválida (%d)", p.Memory)
+	}
+	return nil
+}
+
+// lerCargaTexto lê o formato de processos.txt: "begin duration priority [nome]"
+// Linhas em branco são ignoradas e "#" inicia um comentário
+func lerCargaTexto(r io.Reader) ([]Processes, []int, error) {
+	var processos []Processes
+	var linhas []int
+
+	scanner := bufio.NewScanner(r)
+	numero := 0
+	for scanner.Scan() {
+		numero++
+
+		texto := scanner.Text()
+		if i := strings.Index(texto, "#"); i >= 0 {
+			texto = texto[:i]
+		}
+		campos := strings.Fields(texto)
+		if len(campos) == 0 {
+			continue
+		}
+		if len(campos) < 3 || len(campos) > 4 {
+			return nil, nil, fmt.Errorf("linha %d: esperado \"begin duration priority [nome]\", encontrados %d campos", numero, len(campos))
+		}
+
+		valores := make([]int, 3)
+		for j, nome := range []string{"begin", "duration", "priority"} {
+			v, err := strconv.Atoi(campos[j])
+			if err != nil {
+				return nil, nil, fmt.Errorf("linha %d: %s inválido: %q", numero, nome, campos[j])
+			}
+			valores[j] = v
+		}
+
+		p := Processes{Begin: valores[0], Duration: valores[1], Priority: valores[2]}
+		if len(campos) == 4 {
+			p.Name = campos[3]
+		}
+		processos = append(processos, p)
+		linhas = append(linhas, numero)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return processos, linhas, nil
+}
+
+// lerCargaCSV lê um CSV com cabeçalho; as colunas podem vir em qualquer ordem
+// e também são aceitos os nomes em português (inicio, duracao, prioridade, nome, usuario, grupo, classe, memoria)
+// Colunas "label.<chave>" (ou "rotulo.<chave>") viram rótulos livres do processo
+func lerCargaCSV(r io.Reader) ([]Processes, []int, error) {
+	leitor := csv.NewReader(r)
+	leitor.Comment = '#'
+	leitor.TrimLeadingSpace = true
+
+	cabecalho, err := leitor.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, erroCSV(err)
+	}
+
+	colunas := map[string]int{}
+	rotulos := map[string]int{} // Chave do rótulo -> coluna
+	for i, nome := range cabecalho {
+		nome = strings.TrimSpace(nome)
+		if chave, ok := cortarPrefixo(nome, "label.", "rotulo."); ok {
+			rotulos[chave] = i
+			continue
+		}
+
+		switch strings.ToLower(nome) {
+		case "begin", "inicio", "chegada":
+			colunas["begin"] = i
+		case "duration", "duracao":
+			colunas["duration"] = i
+		case "priority", "prioridade":
+			colunas["priority"] = i
+		case "name", "nome":
+			colunas["name"] = i
+		case "id":
+			colunas["id"] = i
+		case "user", "usuario":
+			colunas["user"] = i
+		case "group", "grupo":
+			colunas["group"] = i
+		case "class", "classe":
+			colunas["class"] = i
+		case "memory", "memoria":
+			colunas["memory"] = i
+		default:
+			return nil, nil, fmt.Errorf("linha 1: coluna desconhecida %q", nome)
+		}
+	}
+	for _, obrigatoria := range []string{"begin", "duration", "priority"} {
+		if _, ok := colunas[obrigatoria]; !ok {
+			return nil, nil, fmt.Errorf("linha 1: coluna %q ausente no cabeçalho", obrigatoria)
+		}
+	}
+
+	var processos []Processes
+	var linhas []int
+	for {
+		registro, err := leitor.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, erroCSV(err)
+		}
+		numero, _ := leitor.FieldPos(0)
+
+		valores := map[string]int{}
+		for _, nome := range []string{"begin", "duration", "priority"} {
+			campo := strings.TrimSpace(registro[colunas[nome]])
+			v, err := strconv.Atoi(campo)
+			if err != nil {
+				return nil, nil, fmt.Errorf("linha %d: %s inválido: %q", numero, nome, campo)
+			}
+			valores[nome] = v
+		}
+
+		p := Processes{Begin: valores["begin"], Duration: valores["duration"], Priority: valores["priority"]}
+		texto := func(coluna string) string {
+			if i, ok := colunas[coluna]; ok {
+				return strings.TrimSpace(registro[i])
+			}
+			return ""
+		}
+		p.Name, p.Id, p.User, p.Group, p.Class = texto("name"), texto("id"), texto("user"), texto("group"), texto("class")
+		if campo := texto("memory"); campo != "" {
+			if p.Memory, err = strconv.Atoi(campo); err != nil {
+				return nil, nil, fmt.Errorf("linha %d: memory inválido: %q", numero, campo)
+			}
+		}
+
+		for chave, i := range rotulos {
+			if valor := strings.TrimSpace(registro[i]); valor != "" {
+				if p.Labels == nil {
+					p.Labels = map[string]string{}
+				}
+				p.Labels[chave] = valor
+			}
+		}
+		processos = append(processos, p)
+		linhas = append(linhas, numero)
+	}
+
+	return processos, linhas, nil
+}
+
+// cortarPrefixo remove o primeiro dos prefixos encontrado no início do texto
+func cortarPrefixo(texto string, prefixos ...string) (string, bool) {
+	for _, prefixo := range prefixos {
+		if strings.HasPrefix(strings.ToLower(texto), prefixo) && len(texto) > len(prefixo) {
+			return texto[len(prefixo):], true
+		}
+	}
+	return "", false
+}
+
+// erroCSV reescreve os erros do pacote csv no mesmo padrão "linha N: ..." dos outros formatos
+func erroCSV(err error) error {
+	var erroLeitura *csv.ParseError
+	if errors.As(err, &erroLeitura) {
+		return fmt.Errorf("linha %d: %v", erroLeitura.Line, erroLeitura.Err)
+	}
+	return err
+}
+
+// cargaEstruturada é o formato aceito em JSON e YAML: uma lista de processos
+// ou um objeto com o campo "input", como o corpo de POST /processes
+type cargaEstruturada struct {
+	Input []Processes `json:"input"`
+}
+
+// lerCargaJSON lê a carga em JSON, convertendo a posição dos erros em número de linha
+func lerCargaJSON(r io.Reader) ([]Processes, []int, error) {
+	dados, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Uma lista de processos ou um objeto com o campo "input"
+	var processos []Processes
+	if texto := bytes.TrimSpace(dados); len(texto) > 0 && texto[0] == '[' {
+		if err := json.Unmarshal(dados, &processos); err != nil {
+			return nil, nil, erroJSON(dados, err)
+		}
+	} else {
+		var carga cargaEstruturada
+		if err := json.Unmarshal(dados, &carga); err != nil {
+			return nil, nil, erroJSON(dados, err)
+		}
+		processos = carga.Input
+	}
+
+	return processos, linhasDosItens(dados, len(processos)), nil
+}
+
+// erroJSON acrescenta a linha do erro, calculada a partir da posição em bytes
+func erroJSON(dados []byte, err error) error {
+	var erroSintaxe *json.SyntaxError
+	var erroTipo *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &erroSintaxe):
+		return fmt.Errorf("linha %d: %v", linhaNaPosicao(dados, erroSintaxe.Offset), err)
+	case errors.As(err, &erroTipo):
+		return fmt.Errorf("linha %d: campo %q deve ser %s", linhaNaPosicao(dados, erroTipo.Offset), erroTipo.Field, erroTipo.Type)
+	}
+	return err
+}
+
+// linhaNaPosicao conta em qual linha está o byte de posição offset
+func linhaNaPosicao(dados []byte, offset int64) int {
+	offset = min(offset, int64(len(dados)))
+	return bytes.Count(dados[:offset], []byte("\n")) + 1
+}
+
+// linhasDosItens encontra a linha em que começa cada objeto da lista de processos,
+// para que os erros de validação apontem para o processo certo
+func linhasDosItens(dados []byte, quantidade int) []int {
+	linhas := make([]int, 0, quantidade)
+	decoder := json.NewDecoder(bytes.NewReader(dados))
+
+	// Avança até a abertura da lista de processos: no topo ou no campo "input"
+	token, err := decoder.Token()
+	if err == nil && token == json.Delim('{') {
+		for decoder.More() {
+			chave, err := decoder.Token()
+			if err != nil {
+				break
+			}
+			if chave == "input" {
+				token, _ = decoder.Token()
+				break
+			}
+			var ignorado json.RawMessage
+			if err := decoder.Decode(&ignorado); err != nil {
+				break
+			}
+		}
+	}
+
+	if token == json.Delim('[') {
+		for decoder.More() && len(linhas) < quantidade {
+			// O offset aponta para o fim do item anterior; pula espaços e a vírgula
+			offset := decoder.InputOffset()
+			resto := dados[offset:]
+			offset += int64(len(resto) - len(bytes.TrimLeft(resto, " \t\r\n,")))
+
+			var item json.RawMessage
+			if err := decoder.Decode(&item); err != nil {
+				break
+			}
+			linhas = append(linhas, linhaNaPosicao(dados, offset))
+		}
+	}
+
+	// Garante uma linha para cada processo mesmo se a varredura falhar
+	for len(linhas) < quantidade {
+		linhas = append(linhas, 1)
+	}
+	return linhas
+}
+
+// lerCargaYAML lê a carga em YAML, com a mesma estrutura aceita em JSON
+func lerCargaYAML(r io.Reader) ([]Processes, []int, error) {
+	dados, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Uma lista de processos ou um objeto com o campo "input"
+	var processos []Processes
+	if ehListaYAML(dados) {
+		if err := yaml.Unmarshal(dados, &processos); err != nil {
+			return nil, nil, erroYAML(err)
+		}
+	} else {
+		var carga cargaEstruturada
+		if err := yaml.Unmarshal(dados, &carga); err != nil {
+			return nil, nil, erroYAML(err)
+		}
+		processos = carga.Input
+	}
+
+	return processos, linhasDosItensYAML(dados, len(processos)), nil
+}
+
+// erroYAML reescreve os erros do decodificador YAML no padrão "linha N: ..."
+func erroYAML(err error) error {
+	var erroDecodificacao yaml.Error
+	if errors.As(err, &erroDecodificacao) && erroDecodificacao.GetToken() != nil {
+		return fmt.Errorf("linha %d: %s", erroDecodificacao.GetToken().Position.Line, erroDecodificacao.GetMessage())
+	}
+	return err
+}
+
+// ehListaYAML verifica se o documento começa por uma lista, ignorando comentários
+func ehListaYAML(dados []byte) bool {
+	for _, linha := range strings.Split(string(dados), "\n") {
+		linha = strings.TrimSpace(linha)
+		if linha == "" || strings.HasPrefix(linha, "#") || linha == "---" {
+			continue
+		}
+		return strings.HasPrefix(linha, "-") || strings.HasPrefix(linha, "[")
+	}
+	return false
+}
+
+// linhasDosItensYAML encontra a linha de cada item da lista ("- ...") de processos
+func linhasDosItensYAML(dados []byte, quantidade int) []int {
+	linhas := make([]int, 0, quantidade)
+	indentacao := -1
+	for i, linha := range strings.Split(string(dados), "\n") {
+		conteudo := strings.TrimLeft(linha, " ")
+		if !strings.HasPrefix(conteudo, "- ") && conteudo != "-" {
+			continue
+		}
+		// Só conta os itens do primeiro nível de lista encontrado
+		nivel := len(linha) - len(conteudo)
+		if indentacao == -1 {
+			indentacao = nivel
+		}
+		if nivel == indentacao && len(linhas) < quantidade {
+			linhas = append(linhas, i+1)
+		}
+	}
+
+	for len(linhas) < quantidade {
+		linhas = append(linhas, 1)
+	}
+	return linhas
+}
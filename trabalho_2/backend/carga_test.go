@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"maps"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mesmaCarga compara os campos que os formatos de arquivo preenchem
+func mesmaCarga(a, b []Processes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Begin != b[i].Begin || a[i].Duration != b[i].Duration || a[i].Priority != b[i].Priority ||
+			a[i].Name != b[i].Name || !maps.Equal(a[i].Labels, b[i].Labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLerCarga(t *testing.T) {
+	// A mesma carga nos quatro formatos; o texto não tem rótulos
+	esperado := []Processes{
+		{Begin: 0, Duration: 5, Priority: 2},
+		{Begin: 1, Duration: 3, Priority: 1, Name: "editor", Labels: map[string]string{"fila": "io"}},
+	}
+	semRotulos := []Processes{esperado[0], {Begin: 1, Duration: 3, Priority: 1, Name: "editor"}}
+
+	casos := []struct {
+		formato  string
+		conteudo string
+		carga    []Processes
+		erro     string
+	}{
+		{formatoTexto, "0 5 2\n# comentário\n\n1 3 1 editor # fim\n", semRotulos, ""},
+		{formatoCSV, "prioridade,inicio,duracao,nome,label.fila\n2,0,5,,\n1,1,3,editor,io\n", esperado, ""},
+		{formatoJSON, `[{"begin":0,"duration":5,"priority":2},` + "\n" + `{"begin":1,"duration":3,"priority":1,"name":"editor","labels":{"fila":"io"}}]`, esperado, ""},
+		{formatoYAML, "- begin: 0\n  duration: 5\n  priority: 2\n- begin: 1\n  duration: 3\n  priority: 1\n  name: editor\n  labels:\n    fila: io\n", esperado, ""},
+
+		// Os erros apontam a linha do processo
+		{formatoTexto, "0 5 2\n1 x 1\n", nil, `linha 2: duration inválido: "x"`},
+		{formatoTexto, "0 5 2\n1 0 1\n", nil, "linha 2: duração inválida (0)"},
+		{formatoCSV, "begin,duration\n0,5\n", nil, `linha 1: coluna "priority" ausente no cabeçalho`},
+		{formatoCSV, "begin,duration,priority\n0,5,2\n1,-3,1\n", nil, "linha 3: duração inválida (-3)"},
+		{formatoJSON, "{\"input\": [\n{\"begin\":0,\"duration\":5,\"priority\":2},\n{\"begin\":1,\"duration\":0,\"priority\":1}]}", nil, "linha 3: duração inválida (0)"},
+		{formatoJSON, "[{\"begin\":0,\n\"duration\":\"x\"}]", nil, `linha 2: campo "0.duration" deve ser int`},
+		{formatoYAML, "input:\n  - {begin: 0, duration: 5, priority: 2}\n  - {begin: 1, duration: -1, priority: 1}\n", nil, "linha 3: duração inválida (-1)"},
+		{formatoTexto, "# só comentário\n", nil, "arquivo vazio"},
+		{"xml", "<x/>", nil, "formato de carga inválido: xml"},
+	}
+
+	for _, c := range casos {
+		carga, err := lerCarga(c.formato, strings.NewReader(c.conteudo))
+		if c.erro != "" {
+			if err == nil || err.Error() != c.erro {
+				t.Errorf("%s %q: erro %v, esperado %q", c.formato, c.conteudo, err, c.erro)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.formato, err)
+			continue
+		}
+		if !mesmaCarga(carga, c.carga) {
+			t.Errorf("%s: %+v, esperado %+v", c.formato, carga, c.carga)
+		}
+	}
+}
+
+func TestFormatoPorExtensao(t *testing.T) {
+	casos := map[string]string{
+		"processos.txt": formatoTexto, "carga": formatoTexto, "CARGA.CSV": formatoCSV,
+		"a.json": formatoJSON, "a.yml": formatoYAML, "a.yaml": formatoYAML, "a.xlsx": "",
+	}
+	for nome, esperado := range casos {
+		formato, err := formatoPorExtensao(nome)
+		if formato != esperado || (esperado == "") != (err != nil) {
+			t.Errorf("%s: formato %q (erro %v), esperado %q", nome, formato, err, esperado)
+		}
+	}
+}
+
+func TestFormularioUpload(t *testing.T) {
+	requisicao := func(campos map[string]string) *gin.Context {
+		var b bytes.Buffer
+		w := multipart.NewWriter(&b)
+		for campo, valor := range campos {
+			w.WriteField(campo, valor)
+		}
+		arquivo, _ := w.CreateFormFile("arquivo", "carga.txt")
+		arquivo.Write([]byte("0 5 2\n1 3 1\n"))
+		w.Close()
+
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/processes/upload", &b)
+		c.Request.Header.Set("Content-Type", w.FormDataContentType())
+		return c
+	}
+
+	// As opções do corpo JSON vêm em "options"; os campos avulsos têm precedência
+	body, err := lerFormularioUpload(requisicao(map[string]string{
+		"options": `{"alg": "rr", "quantum": 4, "priorityOrder": "lower", "maxTime": 50}`,
+		"alg":     "pcpp",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.Alg != "pcpp" || body.Quantum != 4 || body.PriorityOrder != "lower" || body.MaxTime != 50 || len(body.Input) != 2 {
+		t.Errorf("corpo %+v", body)
+	}
+
+	// Sem quantum em lugar nenhum, o padrão é 1
+	if body, err = lerFormularioUpload(requisicao(map[string]string{"alg": "rr"})); err != nil || body.Quantum != 1 {
+		t.Errorf("quantum %d (erro %v), esperado 1", body.Quantum, err)
+	}
+
+	for _, campos := range []map[string]string{{"options": "{alg"}, {"quantum": "x"}, {"aging": "x"}} {
+		if _, err := lerFormularioUpload(requisicao(campos)); err == nil {
+			t.Errorf("%v deveria ser inválido", campos)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// ChromeTrace exporta a simulação no formato Chrome Trace Event,
+// que pode ser aberto em chrome://tracing ou no Perfetto UI
+type ChromeTrace struct {
+	porCPU bool // Inclui uma trilha para a CPU mostrando quem executou em cada intervalo
+}
+
+// Cada unidade de tempo da simulação vira 1 ms no visualizador (o formato usa microssegundos)
+const microssegundosPorUnidade = 1000
+
+// Identificadores de "processo" do formato, usados para agrupar as trilhas no visualizador
+const (
+	pidProcessos = 1
+	pidCPU       = 2
+)
+
+// eventoTrace é um evento do formato Chrome Trace Event
+type eventoTrace struct {
+	Nome      string         `json:"name"`
+	Categoria string         `json:"cat,omitempty"`
+	Fase      string         `json:"ph"`
+	Inicio    int            `json:"ts"`
+	Duracao   int            `json:"dur,omitempty"`
+	Pid       int            `json:"pid"`
+	Tid       int            `json:"tid"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+func (e *ChromeTrace) tipoConteudo() string { return "application/json" }
+func (e *ChromeTrace) extensao() string     { return "json" }
+
+// exportar gera um evento completo ("X") por segmento de execução ou espera,
+// com uma trilha (tid) para cada processo
+func (e *ChromeTrace) exportar(r Resultado) ([]byte, error) {
+	linha := linhaDoTempoDoResultado(r)
+	eventos := make([]eventoTrace, 0)
+
+	// Metadados com os nomes exibidos pelo visualizador
+	eventos = append(eventos, metadadoTrace("process_name", pidProcessos, 0, "Processos"))
+	for i, nome := range linha.Trilhas {
+		eventos = append(eventos, metadadoTrace("thread_name", pidProcessos, i+1, nome))
+		eventos = append(eventos, eventoTrace{
+			Nome: "thread_sort_index", Fase: "M", Pid: pidProcessos, Tid: i + 1,
+			Args: map[string]any{"sort_index": i},
+		})
+	}
+
+	for _, seg := range linha.Segmentos {
+		if seg.Trilha < 0 {
+			continue
+		}
+		eventos = append(eventos, eventoTrace{
+			Nome:      seg.Estado,
+			Categoria: seg.Estado,
+			Fase:      "X",
+			Inicio:    seg.Inicio * microssegundosPorUnidade,
+			Duracao:   (seg.Fim - seg.Inicio) * microssegundosPorUnidade,
+			Pid:       pidProcessos,
+			Tid:       seg.Trilha + 1,
+			Args:      argumentosTrace(r, seg),
+		})
+	}
+
+	if e.porCPU {
+		eventos = append(eventos, metadadoTrace("process_name", pidCPU, 0, "CPU"))
+		eventos = append(eventos, metadadoTrace("thread_name", pidCPU, 0, "CPU 0"))
+		for _, seg := range linha.execucaoNaCPU() {
+			nome := estadoOcioso
+			if seg.Trilha >= 0 {
+				nome = linha.Trilhas[seg.Trilha]
+			}
+			eventos = append(eventos, eventoTrace{
+				Nome:      nome,
+				Categoria: seg.Estado,
+				Fase:      "X",
+				Inicio:    seg.Inicio * microssegundosPorUnidade,
+				Duracao:   (seg.Fim - seg.Inicio) * microssegundosPorUnidade,
+				Pid:       pidCPU,
+				Tid:       0,
+			})
+		}
+	}
+
+	return json.Marshal(map[string]any{
+		"traceEvents":     eventos,
+		"displayTimeUnit": "ms",
+		"otherData": map[string]any{
+			"tempoMedioVida":   r.TempoMedioVida,
+			"tempoMedioEspera": r.TempoMedioEspera,
+			"trocasContexto":   r.TrocasContexto,
+		},
+	})
+}
+
+// argumentosTrace monta os detalhes exibidos ao selecionar um segmento,
+// incluindo os metadados do processo vindos da carga de trabalho
+func argumentosTrace(r Resultado, seg Segmento) map[string]any {
+	args := map[string]any{"inicio": seg.Inicio, "fim": seg.Fim}
+	if seg.Trilha >= len(r.Processos) {
+		return args
+	}
+
+	p := r.Processos[seg.Trilha]
+	args["processo"] = p.Processo
+	for chave, valor := range map[string]string{"id": p.Id, "usuario": p.Usuario, "grupo": p.Grupo, "classe": p.Classe} {
+		if valor != "" {
+			args[chave] = valor
+		}
+	}
+	for chave, valor := range p.Rotulos {
+		args["rotulo."+chave] = valor
+	}
+	return args
+}
+
+// metadadoTrace cria um evento de metadados ("M") que dá nome a um processo ou trilha
+func metadadoTrace(tipo string, pid, tid int, nome string) eventoTrace {
+	return eventoTrace{
+		Nome: tipo,
+		Fase: "M",
+		Pid:  pid,
+		Tid:  tid,
+		Args: map[string]any{"name": nome},
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// resultadoDeTeste é um diagrama pequeno: A executa, espera B e volta depois de um instante ocioso
+func resultadoDeTeste() Resultado {
+	return Resultado{
+		OrdemProcessos: []string{"A ", "B "},
+		DiagramaTempo: [][]string{
+			{"##", "  "},
+			{"--", "##"},
+			{"--", "##"},
+			{"  ", "  "},
+			{"##", "  "},
+		},
+		Processos: []MetricasProcesso{
+			{Processo: "A", Nome: "A", Usuario: "ana", Rotulos: map[string]string{"fila": "io"}},
+			{Processo: "B", Nome: "B"},
+		},
+		TempoMedioVida: 3.5,
+	}
+}
+
+func TestLinhaDoTempoDoResultado(t *testing.T) {
+	linha := linhaDoTempoDoResultado(resultadoDeTeste())
+
+	if !slices.Equal(linha.Trilhas, []string{"A", "B"}) || linha.Fim != 5 {
+		t.Fatalf("trilhas %q até %d", linha.Trilhas, linha.Fim)
+	}
+	esperado := []Segmento{
+		{0, 0, 1, estadoExecutando},
+		{0, 1, 3, estadoEsperando},
+		{0, 4, 5, estadoExecutando},
+		{1, 1, 3, estadoExecutando},
+		{-1, 3, 4, estadoOcioso},
+	}
+	if !slices.Equal(linha.Segmentos, esperado) {
+		t.Errorf("segmentos %+v, esperado %+v", linha.Segmentos, esperado)
+	}
+
+	cpu := []Segmento{
+		{0, 0, 1, estadoExecutando},
+		{1, 1, 3, estadoExecutando},
+		{-1, 3, 4, estadoOcioso},
+		{0, 4, 5, estadoExecutando},
+	}
+	if !slices.Equal(linha.execucaoNaCPU(), cpu) {
+		t.Errorf("execução na CPU %+v, esperado %+v", linha.execucaoNaCPU(), cpu)
+	}
+}
+
+func TestChromeTrace(t *testing.T) {
+	saida, err := (&ChromeTrace{porCPU: true}).exportar(resultadoDeTeste())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trace struct {
+		TraceEvents     []eventoTrace  `json:"traceEvents"`
+		DisplayTimeUnit string         `json:"displayTimeUnit"`
+		OtherData       map[string]any `json:"otherData"`
+	}
+	if err := json.Unmarshal(saida, &trace); err != nil {
+		t.Fatal(err)
+	}
+	if trace.DisplayTimeUnit != "ms" || trace.OtherData["tempoMedioVida"] != 3.5 {
+		t.Errorf("displayTimeUnit %q e otherData %v", trace.DisplayTimeUnit, trace.OtherData)
+	}
+
+	type intervalo struct {
+		nome        string
+		pid, tid    int
+		inicio, fim int
+	}
+	var completos []intervalo
+	nomesDasTrilhas := map[int]string{}
+	for _, e := range trace.TraceEvents {
+		switch {
+		case e.Fase == "X":
+			completos = append(completos, intervalo{e.Nome, e.Pid, e.Tid, e.Inicio, e.Inicio + e.Duracao})
+		case e.Nome == "thread_name" && e.Pid == pidProcessos:
+			nomesDasTrilhas[e.Tid] = e.Args["name"].(string)
+		}
+	}
+
+	// Cada unidade de tempo vale 1 ms (1000 µs); as trilhas dos processos começam em 1
+	esperado := []intervalo{
+		{estadoExecutando, pidProcessos, 1, 0, 1000},
+		{estadoEsperando, pidProcessos, 1, 1000, 3000},
+		{estadoExecutando, pidProcessos, 1, 4000, 5000},
+		{estadoExecutando, pidProcessos, 2, 1000, 3000},
+		{"A", pidCPU, 0, 0, 1000},
+		{"B", pidCPU, 0, 1000, 3000},
+		{estadoOcioso, pidCPU, 0, 3000, 4000},
+		{"A", pidCPU, 0, 4000, 5000},
+	}
+	if !slices.Equal(completos, esperado) {
+		t.Errorf("eventos %+v, esperado %+v", completos, esperado)
+	}
+	if nomesDasTrilhas[1] != "A" || nomesDasTrilhas[2] != "B" {
+		t.Errorf("nomes das trilhas %v", nomesDasTrilhas)
+	}
+
+	// Os metadados da carga aparecem nos argumentos do segmento
+	for _, e := range trace.TraceEvents {
+		if e.Fase == "X" && e.Pid == pidProcessos && e.Tid == 1 {
+			if e.Args["usuario"] != "ana" || e.Args["rotulo.fila"] != "io" || e.Args["processo"] != "A" {
+				t.Errorf("argumentos de A %v", e.Args)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+// Clock (segunda chance) percorre os quadros em círculo: páginas com o bit R ligado
+// ganham uma segunda chance (o bit é desligado) e a primeira com R desligado é substituída
+type Clock struct {
+	s        *SimuladorPaginacao
+	bitR     []bool // Bit de referência de cada quadro
+	ponteiro int    // Próximo quadro examinado
+}
+
+func (alg *Clock) escolherVitima() int {
+	for alg.bitR[alg.ponteiro] {
+		alg.bitR[alg.ponteiro] = false
+		alg.ponteiro = (alg.ponteiro + 1) % len(alg.bitR)
+	}
+	return alg.ponteiro
+}
+
+func (alg *Clock) aoAcessar(quadro int, falta bool) {
+	alg.bitR[quadro] = true
+
+	// Depois de uma carga, o ponteiro segue para o quadro seguinte
+	if falta && quadro == alg.ponteiro {
+		alg.ponteiro = (alg.ponteiro + 1) % len(alg.bitR)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// CLOOK é o C-SCAN que só vai até a última requisição em cada sentido: dali o braço volta
+// direto para a requisição pendente mais distante no outro lado e recomeça a varredura
+type CLOOK struct {
+	s *SimuladorDisco
+}
+
+func (alg *CLOOK) proximoMovimento() Movimento {
+	s := alg.s
+	if r := s.maisProxima(s.direcao); r != nil {
+		return Movimento{destino: r.cilindro, requisicao: r}
+	}
+
+	// Todas as pendentes ficaram para trás; a mais distante é onde a nova varredura começa
+	var inicio *Requisicao
+	for _, r := range s.pendentes() {
+		if inicio == nil || abs(r.cilindro-s.cabeca) > abs(inicio.cilindro-s.cabeca) {
+			inicio = r
+		}
+	}
+	return Movimento{destino: inicio.cilindro, retorno: true}
+}
@@ -0,0 +1,18 @@
+package main
+
+// CSCAN atende as requisições sempre no mesmo sentido: ao chegar ao fim do disco,
+// o braço volta ao outro extremo sem atender nada e recomeça a varredura
+type CSCAN struct {
+	s *SimuladorDisco
+}
+
+func (alg *CSCAN) proximoMovimento() Movimento {
+	s := alg.s
+	if r := s.maisProxima(s.direcao); r != nil {
+		return Movimento{destino: r.cilindro, requisicao: r}
+	}
+	if fim := s.extremo(s.direcao); s.cabeca != fim {
+		return Movimento{destino: fim}
+	}
+	return Movimento{destino: s.extremo(-s.direcao), retorno: true}
+}
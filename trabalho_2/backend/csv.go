@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSV exporta uma das tabelas da simulação:
+// as métricas por processo ou a linha do tempo em segmentos
+type CSV struct {
+	tabela string // "processos" (padrão) ou "diagrama"
+}
+
+func (e *CSV) tipoConteudo() string { return "text/csv; charset=utf-8" }
+func (e *CSV) extensao() string     { return "csv" }
+
+func (e *CSV) exportar(r Resultado) ([]byte, error) {
+	var linhas [][]string
+
+	switch e.tabela {
+	case "", "processos":
+		linhas = append(linhas, []string{"processo", "indice", "id", "nome", "usuario", "grupo", "classe", "rotulos",
+			"chegada", "duracao", "prioridade", "inicio", "termino", "tempo_vida", "tempo_espera", "tempo_resposta", "slowdown", "maior_espera_continua", "inanicao"})
+		for _, p := range r.Processos {
+			linhas = append(linhas, []string{
+				p.Processo,
+				strconv.Itoa(p.Indice),
+				p.Id,
+				p.Nome,
+				p.Usuario,
+				p.Grupo,
+				p.Classe,
+				juntarRotulos(p.Rotulos),
+				strconv.Itoa(p.Chegada),
+				strconv.Itoa(p.Duracao),
+				strconv.Itoa(p.Prioridade),
+				strconv.Itoa(p.Inicio),
+				strconv.Itoa(p.Termino),
+				strconv.Itoa(p.TempoVida),
+				strconv.Itoa(p.TempoEspera),
+				strconv.Itoa(p.TempoResposta),
+				strconv.FormatFloat(p.Slowdown, 'f', -1, 64),
+				strconv.Itoa(p.MaiorEsperaContinua),
+				strconv.FormatBool(p.Inanicao),
+			})
+		}
+	case "diagrama":
+		// Um segmento por linha; os intervalos ociosos aparecem na trilha "CPU"
+		linha := linhaDoTempoDoResultado(r)
+		linhas = append(linhas, []string{"trilha", "estado", "inicio", "fim"})
+		for _, seg := range linha.Segmentos {
+			trilha := "CPU"
+			if seg.Trilha >= 0 {
+				trilha = linha.Trilhas[seg.Trilha]
+			}
+			linhas = append(linhas, []string{trilha, seg.Estado, strconv.Itoa(seg.Inicio), strconv.Itoa(seg.Fim)})
+		}
+	default:
+		return nil, fmt.Errorf("tabela inválida para exportação CSV")
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(linhas); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// juntarRotulos escreve os rótulos como "chave=valor" separados por ";", em ordem de chave
+func juntarRotulos(rotulos map[string]string) string {
+	chaves := make([]string, 0, len(rotulos))
+	for chave := range rotulos {
+		chaves = append(chaves, chave)
+	}
+	sort.Strings(chaves)
+
+	pares := make([]string, len(chaves))
+	for i, chave := range chaves {
+		pares[i] = chave + "=" + rotulos[chave]
+	}
+	return strings.Join(pares, ";")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSV(t *testing.T) {
+	r := resultadoDeTeste()
+	r.Processos[0] = MetricasProcesso{Processo: "A", Indice: 1, Nome: "A", Usuario: "ana", Rotulos: map[string]string{"z": "1", "fila": "io"},
+		Chegada: 0, Duracao: 2, Prioridade: 3, Inicio: 0, Termino: 5, TempoVida: 5, TempoEspera: 3, TempoResposta: 0, Slowdown: 2.5, MaiorEsperaContinua: 2}
+	r.Processos[1] = MetricasProcesso{Processo: "B, o segundo", Indice: 2, Chegada: 1, Duracao: 2, Prioridade: 1, Inicio: 1, Termino: 3, TempoVida: 2, Slowdown: 1}
+
+	casos := []struct {
+		tabela string
+		linhas []string
+	}{
+		{"processos", []string{
+			"processo,indice,id,nome,usuario,grupo,classe,rotulos,chegada,duracao,prioridade,inicio,termino,tempo_vida,tempo_espera,tempo_resposta,slowdown,maior_espera_continua,inanicao",
+			"A,1,,A,ana,,,fila=io;z=1,0,2,3,0,5,5,3,0,2.5,2,false",
+			`"B, o segundo",2,,,,,,,1,2,1,1,3,2,0,0,1,0,false`,
+		}},
+		{"diagrama", []string{
+			"trilha,estado,inicio,fim",
+			"A,executando,0,1",
+			"A,esperando,1,3",
+			"A,executando,4,5",
+			"B,executando,1,3",
+			"CPU,ocioso,3,4",
+		}},
+	}
+
+	for _, c := range casos {
+		saida, err := (&CSV{c.tabela}).exportar(r)
+		if err != nil {
+			t.Fatalf("%s: %v", c.tabela, err)
+		}
+		if esperado := strings.Join(c.linhas, "\n") + "\n"; string(saida) != esperado {
+			t.Errorf("%s:\n%s\nesperado:\n%s", c.tabela, saida, esperado)
+		}
+	}
+
+	if _, err := (&CSV{"fila"}).exportar(r); err == nil {
+		t.Error("a tabela \"fila\" deveria ser inválida")
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+// Desempenho reúne as métricas clássicas de desempenho do escalonador
+type Desempenho struct {
+	Makespan           int           `json:"makespan"`      // Do primeiro processo a chegar ao último a terminar
+	TempoOcupado       int           `json:"tempoOcupado"`  // Unidades de tempo com algum processo na CPU
+	TempoOcioso        int           `json:"tempoOcioso"`   // Unidades de tempo com a CPU ociosa
+	UtilizacaoCPU      float64       `json:"utilizacaoCPU"` // Fração do tempo simulado com a CPU ocupada
+	Vazao              float64       `json:"vazao"`         // Processos concluídos por unidade de tempo, no makespan
+	JanelaVazao        int           `json:"janelaVazao,omitempty"`
+	VazaoPorJanela     []JanelaVazao `json:"vazaoPorJanela,omitempty"`
+	SlowdownMedio      float64       `json:"slowdownMedio"` // Média do tempo de vida normalizado pela duração
+	SlowdownMaximo     float64       `json:"slowdownMaximo"`
+	TempoMedioResposta float64       `json:"tempoMedioResposta"` // Média do tempo entre a chegada e a primeira execução
+}
+
+// JanelaVazao conta os processos concluídos no intervalo [Inicio, Fim)
+type JanelaVazao struct {
+	Inicio     int     `json:"inicio"`
+	Fim        int     `json:"fim"`
+	Concluidos int     `json:"concluidos"`
+	Vazao      float64 `json:"vazao"` // Concluídos por unidade de tempo na janela
+}
+
+// registrarOcupacao conta se a CPU ficou ocupada ou ociosa neste instante
+func (s *Simulador) registrarOcupacao(processoAtual *Processo) {
+	if processoAtual == nil {
+		s.tempoOcioso++
+	} else {
+		s.tempoOcupado++
+	}
+}
+
+// calcularDesempenho calcula makespan, utilização, vazão, slowdown e tempo de resposta
+// A vazão também é dividida em janelas de tamanho janela (0 desativa a divisão)
+func (s *Simulador) calcularDesempenho(metricas []MetricasProcesso, janela int) Desempenho {
+	d := Desempenho{TempoOcupado: s.tempoOcupado, TempoOcioso: s.tempoOcioso, JanelaVazao: janela}
+
+	if total := s.tempoOcupado + s.tempoOcioso; total > 0 {
+		d.UtilizacaoCPU = float64(s.tempoOcupado) / float64(total)
+	}
+
+	primeiraChegada, ultimoTermino := -1, 0
+	concluidos := 0
+	var somaSlowdown, somaResposta float64
+	for _, m := range metricas {
+		if primeiraChegada == -1 || m.Chegada < primeiraChegada {
+			primeiraChegada = m.Chegada
+		}
+		if !m.Concluido {
+			continue
+		}
+
+		concluidos++
+		ultimoTermino = max(ultimoTermino, m.Termino)
+		somaSlowdown += m.Slowdown
+		somaResposta += float64(m.TempoResposta)
+		d.SlowdownMaximo = max(d.SlowdownMaximo, m.Slowdown)
+	}
+
+	if concluidos == 0 {
+		return d
+	}
+
+	d.Makespan = ultimoTermino - primeiraChegada
+	if d.Makespan > 0 {
+		d.Vazao = float64(concluidos) / float64(d.Makespan)
+	}
+	d.SlowdownMedio = somaSlowdown / float64(concluidos)
+	d.TempoMedioResposta = somaResposta / float64(concluidos)
+
+	if janela > 0 {
+		d.VazaoPorJanela = vazaoPorJanela(metricas, janela, s.tempoAtual)
+	}
+
+	return d
+}
+
+// vazaoPorJanela divide o tempo simulado em janelas consecutivas e conta os términos em cada uma
+// Um processo que termina no instante t conta na janela que contém a unidade de tempo t-1
+func vazaoPorJanela(metricas []MetricasProcesso, janela, fim int) []JanelaVazao {
+	var janelas []JanelaVazao
+	for inicio := 0; inicio < fim; inicio += janela {
+		janelas = append(janelas, JanelaVazao{Inicio: inicio, Fim: min(inicio+janela, fim)})
+	}
+
+	for _, m := range metricas {
+		if m.Concluido {
+			janelas[(m.Termino-1)/janela].Concluidos++
+		}
+	}
+
+	for i := range janelas {
+		j := &janelas[i]
+		j.Vazao = float64(j.Concluidos) / float64(j.Fim-j.Inicio)
+	}
+
+	return janelas
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDesempenho(t *testing.T) {
+	// FCFS: CPU ociosa em 0, A em 1-3, B em 3-6, ociosa em 6-8 e C em 8-9
+	body := ContextBody{Alg: "fcfs", Quantum: 1, ThroughputWindow: 4, Input: []Processes{
+		{Begin: 1, Duration: 2, Priority: 1, Name: "A"},
+		{Begin: 1, Duration: 3, Priority: 1, Name: "B"},
+		{Begin: 8, Duration: 1, Priority: 1, Name: "C"},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := r.Desempenho
+
+	if d.TempoOcupado != 6 || d.TempoOcioso != 3 || d.Makespan != 8 {
+		t.Errorf("ocupado %d, ocioso %d, makespan %d; esperado 6, 3, 8", d.TempoOcupado, d.TempoOcioso, d.Makespan)
+	}
+
+	reais := []struct {
+		nome            string
+		valor, esperado float64
+	}{
+		{"utilização", d.UtilizacaoCPU, 6.0 / 9},
+		{"vazão", d.Vazao, 3.0 / 8},
+		{"slowdown médio", d.SlowdownMedio, (1 + 5.0/3 + 1) / 3}, // B esperou 2 e durou 3
+		{"slowdown máximo", d.SlowdownMaximo, 5.0 / 3},
+		{"resposta média", d.TempoMedioResposta, 2.0 / 3},
+	}
+	for _, c := range reais {
+		if math.Abs(c.valor-c.esperado) > 1e-9 {
+			t.Errorf("%s %v, esperado %v", c.nome, c.valor, c.esperado)
+		}
+	}
+
+	// A última janela é cortada no fim da simulação
+	janelas := []JanelaVazao{
+		{Inicio: 0, Fim: 4, Concluidos: 1, Vazao: 0.25},
+		{Inicio: 4, Fim: 8, Concluidos: 1, Vazao: 0.25},
+		{Inicio: 8, Fim: 9, Concluidos: 1, Vazao: 1},
+	}
+	if !reflect.DeepEqual(d.VazaoPorJanela, janelas) {
+		t.Errorf("janelas %+v, esperado %+v", d.VazaoPorJanela, janelas)
+	}
+}
+
+func TestDesempenhoSemConcluidos(t *testing.T) {
+	s := &Simulador{tempoOcupado: 2, tempoOcioso: 2}
+	d := s.calcularDesempenho([]MetricasProcesso{{Chegada: 0, Concluido: false}}, 0)
+	if d.UtilizacaoCPU != 0.5 || d.Makespan != 0 || d.Vazao != 0 || d.SlowdownMedio != 0 {
+		t.Errorf("desempenho %+v", d)
+	}
+}
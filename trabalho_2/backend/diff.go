@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ReferenciaExecucao aponta para uma execução do histórico ou traz uma simulação para rodar na hora
+type ReferenciaExecucao struct {
+	Id         string       `json:"id"`
+	Simulation *ContextBody `json:"simulation"`
+}
+
+// DiffBody é o corpo de POST /diff
+type DiffBody struct {
+	A ReferenciaExecucao `json:"a"`
+	B ReferenciaExecucao `json:"b"`
+}
+
+// DiferencaParametro é um parâmetro que mudou entre as duas execuções
+type DiferencaParametro struct {
+	Campo string `json:"campo"`
+	A     any    `json:"a"`
+	B     any    `json:"b"`
+}
+
+// IntervaloDivergente é um trecho em que a CPU estava com processos diferentes nas duas execuções
+// Um processo vazio indica CPU ociosa (ou fim da simulação)
+type IntervaloDivergente struct {
+	Inicio int    `json:"inicio"`
+	Fim    int    `json:"fim"`
+	A      string `json:"a"`
+	B      string `json:"b"`
+}
+
+// DeltaProcesso é a diferença (B - A) nas métricas de um processo presente nas duas execuções
+type DeltaProcesso struct {
+	Processo      string  `json:"processo"`
+	Termino       int     `json:"termino"`
+	TempoVida     int     `json:"tempoVida"`
+	TempoEspera   int     `json:"tempoEspera"`
+	TempoResposta int     `json:"tempoResposta"`
+	Slowdown      float64 `json:"slowdown"`
+}
+
+// DecisaoDivergente mostra a primeira decisão do escalonador que mudou entre as execuções
+type DecisaoDivergente struct {
+	Indice int                   `json:"indice"` // Posição da decisão no rastro
+	A      *DecisaoEscalonamento `json:"a"`      // nil se o rastro de A acabou antes
+	B      *DecisaoEscalonamento `json:"b"`
+}
+
+// Diff compara duas execuções
+type Diff struct {
+	ExecucaoA             string                `json:"execucaoA,omitempty"`
+	ExecucaoB             string                `json:"execucaoB,omitempty"`
+	Parametros            []DiferencaParametro  `json:"parametros"`
+	Iguais                bool                  `json:"iguais"` // As linhas do tempo da CPU são idênticas
+	PrimeiraDivergencia   *int                  `json:"primeiraDivergencia,omitempty"`
+	InstantesDivergentes  int                   `json:"instantesDivergentes"`
+	Intervalos            []IntervaloDivergente `json:"intervalos"`
+	DeltaTempoMedioVida   float64               `json:"deltaTempoMedioVida"`
+	DeltaTempoMedioEspera float64               `json:"deltaTempoMedioEspera"`
+	DeltaTrocasContexto   int                   `json:"deltaTrocasContexto"`
+	DeltaMakespan         int                   `json:"deltaMakespan"`
+	Processos             []DeltaProcesso       `json:"processos"`
+	SomenteEmA            []string              `json:"somenteEmA,omitempty"` // Processos que não existem na outra carga
+	SomenteEmB            []string              `json:"somenteEmB,omitempty"`
+	PrimeiraDecisao       *DecisaoDivergente    `json:"primeiraDecisao,omitempty"`
+	RastroIndisponivel    bool                  `json:"rastroIndisponivel,omitempty"` // Alguma execução foi feita sem trace
+}
+
+// carregar refaz a execução do histórico ou roda a simulação informada, sempre com rastro
+func (ref ReferenciaExecucao) carregar(ctx context.Context) (Resultado, error) {
+	switch {
+	case ref.Id != "" && ref.Simulation != nil:
+		return Resultado{}, fmt.Errorf("informe id ou simulation, não os dois")
+	case ref.Id != "":
+		if historico == nil {
+			return Resultado{}, fmt.Errorf("o histórico de execuções está desativado neste servidor")
+		}
+		execucao, err := historico.buscar(ref.Id)
+		if err != nil {
+			return Resultado{}, fmt.Errorf("%s: %v", ref.Id, err)
+		}
+		// O histórico guarda só o resumo; a linha do tempo vem da entrada simulada de novo
+		return execucao.refazer(ctx)
+	case ref.Simulation != nil:
+		body := *ref.Simulation
+		body.Trace = true // As decisões só podem ser comparadas com o rastro
+		if err := validarEntrada(body); err != nil {
+			return Resultado{}, err
+		}
+		return processScheduler(ctx, body, nil)
+	}
+	return Resultado{}, fmt.Errorf("informe o id de uma execução ou uma simulation")
+}
+
+// chaveProcesso identifica o mesmo processo nas duas execuções: pelo id estável ou pela posição na entrada
+func chaveProcesso(m MetricasProcesso) string {
+	if m.Id != "" {
+		return "id:" + m.Id
+	}
+	return fmt.Sprintf("indice:%d", m.Indice)
+}
+
+// ocupacaoCPU devolve, para cada instante, a chave do processo que estava na CPU ("" se ociosa)
+func ocupacaoCPU(r Resultado) []string {
+	ocupacao := make([]string, len(r.DiagramaTempo))
+	for t, linha := range r.DiagramaTempo {
+		for i, estado := range linha {
+			if estado == "##" && i < len(r.Processos) {
+				ocupacao[t] = chaveProcesso(r.Processos[i])
+			}
+		}
+	}
+	return ocupacao
+}
+
+// compararExecucoes monta o diff de B em relação a A
+func compararExecucoes(a, b Resultado) Diff {
+	d := Diff{
+		ExecucaoA:             a.Execucao,
+		ExecucaoB:             b.Execucao,
+		Parametros:            compararParametros(a, b),
+		DeltaTempoMedioVida:   b.Estatisticas.TempoMedioVida - a.Estatisticas.TempoMedioVida,
+		DeltaTempoMedioEspera: b.Estatisticas.TempoMedioEspera - a.Estatisticas.TempoMedioEspera,
+		DeltaTrocasContexto:   b.TrocasContexto - a.TrocasContexto,
+		DeltaMakespan:         b.Desempenho.Makespan - a.Desempenho.Makespan,
+		Intervalos:            []IntervaloDivergente{},
+		Processos:             []DeltaProcesso{},
+	}
+
+	// Rótulo de exibição de cada chave, nas duas execuções
+	rotulos := map[string]string{}
+	metricasA := map[string]MetricasProcesso{}
+	for _, m := range a.Processos {
+		metricasA[chaveProcesso(m)] = m
+		rotulos[chaveProcesso(m)] = m.Processo
+	}
+	for _, m := range b.Processos {
+		if _, ok := rotulos[chaveProcesso(m)]; !ok {
+			rotulos[chaveProcesso(m)] = m.Processo
+		}
+	}
+
+	// Linha do tempo da CPU, instante a instante; a execução mais curta é completada com ociosidade
+	cpuA, cpuB := ocupacaoCPU(a), ocupacaoCPU(b)
+	fim := max(len(cpuA), len(cpuB))
+	for t := 0; t < fim; t++ {
+		var pa, pb string
+		if t < len(cpuA) {
+			pa = cpuA[t]
+		}
+		if t < len(cpuB) {
+			pb = cpuB[t]
+		}
+		if pa == pb {
+			continue
+		}
+
+		d.InstantesDivergentes++
+		if d.PrimeiraDivergencia == nil {
+			d.PrimeiraDivergencia = &t
+		}
+
+		// Junta instantes consecutivos com o mesmo par de processos
+		ra, rb := rotulos[pa], rotulos[pb]
+		if n := len(d.Intervalos); n > 0 {
+			ultimo := &d.Intervalos[n-1]
+			if ultimo.Fim == t && ultimo.A == ra && ultimo.B == rb {
+				ultimo.Fim = t + 1
+				continue
+			}
+		}
+		d.Intervalos = append(d.Intervalos, IntervaloDivergente{t, t + 1, ra, rb})
+	}
+	d.Iguais = d.InstantesDivergentes == 0
+
+	// Diferenças por processo, na ordem de B
+	emB := map[string]bool{}
+	for _, mb := range b.Processos {
+		chave := chaveProcesso(mb)
+		emB[chave] = true
+		ma, ok := metricasA[chave]
+		if !ok {
+			d.SomenteEmB = append(d.SomenteEmB, mb.Processo)
+			continue
+		}
+		d.Processos = append(d.Processos, DeltaProcesso{
+			Processo:      mb.Processo,
+			Termino:       mb.Termino - ma.Termino,
+			TempoVida:     mb.TempoVida - ma.TempoVida,
+			TempoEspera:   mb.TempoEspera - ma.TempoEspera,
+			TempoResposta: mb.TempoResposta - ma.TempoResposta,
+			Slowdown:      mb.Slowdown - ma.Slowdown,
+		})
+	}
+	for _, ma := range a.Processos {
+		if !emB[chaveProcesso(ma)] {
+			d.SomenteEmA = append(d.SomenteEmA, ma.Processo)
+		}
+	}
+
+	d.PrimeiraDecisao, d.RastroIndisponivel = primeiraDecisaoDivergente(a.Rastro, b.Rastro)
+	return d
+}
+
+// primeiraDecisaoDivergente percorre os dois rastros até a primeira decisão diferente
+// Duas decisões são iguais se acontecem no mesmo instante, são do mesmo tipo e envolvem os mesmos processos
+func primeiraDecisaoDivergente(a, b []DecisaoEscalonamento) (*DecisaoDivergente, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return nil, true
+	}
+
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var da, db *DecisaoEscalonamento
+		if i < len(a) {
+			da = &a[i]
+		}
+		if i < len(b) {
+			db = &b[i]
+		}
+		if da != nil && db != nil && da.Instante == db.Instante && da.Tipo == db.Tipo &&
+			da.Escolhido == db.Escolhido && da.Preemptado == db.Preemptado {
+			continue
+		}
+		return &DecisaoDivergente{Indice: i, A: da, B: db}, false
+	}
+	return nil, false
+}
+
+// compararParametros lista os parâmetros do escalonamento que mudaram
+func compararParametros(a, b Resultado) []DiferencaParametro {
+	diferencas := []DiferencaParametro{}
+	adicionar := func(campo string, va, vb any, iguais bool) {
+		if !iguais {
+			diferencas = append(diferencas, DiferencaParametro{campo, va, vb})
+		}
+	}
+
+	adicionar("algoritmo", a.Algoritmo, b.Algoritmo, a.Algoritmo == b.Algoritmo)
+	adicionar("quantum", a.Quantum, b.Quantum, a.Quantum == b.Quantum)
+	adicionar("convencaoPrioridade", a.Regras.ConvencaoPrioridade, b.Regras.ConvencaoPrioridade,
+		a.Regras.ConvencaoPrioridade == b.Regras.ConvencaoPrioridade)
+	adicionar("desempate", a.Regras.Desempate, b.Regras.Desempate, slices.Equal(a.Regras.Desempate, b.Regras.Desempate))
+	adicionar("envelhecimento", a.Envelhecimento, b.Envelhecimento, envelhecimentoIgual(a.Envelhecimento, b.Envelhecimento))
+	adicionar("processos", len(a.Processos), len(b.Processos), len(a.Processos) == len(b.Processos))
+
+	return diferencas
+}
+
+// envelhecimentoIgual compara as configurações de envelhecimento, que podem estar ausentes
+// Só os campos exportados entram na comparação, já que os demais se perdem ao salvar no histórico
+func envelhecimentoIgual(a, b *Envelhecimento) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Estrategia == b.Estrategia && a.Incremento == b.Incremento &&
+		a.Limiar == b.Limiar && a.ResetarAoDespachar == b.ResetarAoDespachar
+}
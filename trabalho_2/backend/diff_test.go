@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestDiffEnvelhecimento(t *testing.T) {
+	// As duas execuções só diferem no envelhecimento: com ele, L passa na frente de H2 em t=3
+	entrada := []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "L"},
+		{Begin: 0, Duration: 3, Priority: 5, Name: "H1"},
+		{Begin: 3, Duration: 3, Priority: 5, Name: "H2"},
+		{Begin: 6, Duration: 3, Priority: 5, Name: "H3"},
+	}
+	semEnvelhecimento := ContextBody{Alg: "psp", Quantum: 1, Input: entrada}
+	comEnvelhecimento := semEnvelhecimento
+	comEnvelhecimento.AgingStrategy, comEnvelhecimento.Aging = envelhecimentoTick, 2
+
+	a, err := ReferenciaExecucao{Simulation: &semEnvelhecimento}.carregar(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReferenciaExecucao{Simulation: &comEnvelhecimento}.carregar(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := compararExecucoes(a, b)
+
+	if len(d.Parametros) != 1 || d.Parametros[0].Campo != "envelhecimento" {
+		t.Fatalf("parâmetros %+v, esperado só envelhecimento", d.Parametros)
+	}
+	if pa, pb := d.Parametros[0].A.(*Envelhecimento), d.Parametros[0].B.(*Envelhecimento); pa != nil || pb == nil ||
+		pb.Estrategia != envelhecimentoTick || pb.Incremento != 2 {
+		t.Errorf("envelhecimento A %+v, B %+v", pa, pb)
+	}
+
+	intervalos := []IntervaloDivergente{{3, 6, "H2", "L"}, {6, 9, "H3", "H2"}, {9, 12, "L", "H3"}}
+	if d.Iguais || d.PrimeiraDivergencia == nil || *d.PrimeiraDivergencia != 3 || d.InstantesDivergentes != 9 ||
+		!reflect.DeepEqual(d.Intervalos, intervalos) {
+		t.Errorf("divergência em %v, %d instantes, intervalos %+v; esperado 3, 9, %+v",
+			d.PrimeiraDivergencia, d.InstantesDivergentes, d.Intervalos, intervalos)
+	}
+
+	termino := map[string]int{}
+	for _, p := range d.Processos {
+		termino[p.Processo] = p.Termino
+	}
+	if esperado := map[string]int{"L": -6, "H1": 0, "H2": 3, "H3": 3}; !reflect.DeepEqual(termino, esperado) {
+		t.Errorf("delta dos términos %v, esperado %v", termino, esperado)
+	}
+	if d.DeltaTempoMedioVida != 0 || d.DeltaMakespan != 0 {
+		t.Errorf("delta do tempo de vida %v, do makespan %d; esperado 0", d.DeltaTempoMedioVida, d.DeltaMakespan)
+	}
+
+	if p := d.PrimeiraDecisao; p == nil || p.A == nil || p.B == nil || p.A.Instante != 3 || p.A.Escolhido != "H2" || p.B.Escolhido != "L" {
+		t.Errorf("primeira decisão divergente %+v", p)
+	}
+}
+
+func TestDiffParametrosEProcessos(t *testing.T) {
+	a := Resultado{Algoritmo: "rr", Quantum: 2, Processos: []MetricasProcesso{
+		{Processo: "x", Id: "x", Termino: 4}, {Processo: "P2", Indice: 2, Termino: 6},
+	}}
+	b := Resultado{Algoritmo: "rr", Quantum: 3, Processos: []MetricasProcesso{
+		{Processo: "P2", Indice: 2, Termino: 5}, {Processo: "y", Id: "y", Termino: 7},
+	}}
+	d := compararExecucoes(a, b)
+
+	parametros := []DiferencaParametro{{"quantum", 2, 3}}
+	if !reflect.DeepEqual(d.Parametros, parametros) {
+		t.Errorf("parâmetros %+v, esperado %+v", d.Parametros, parametros)
+	}
+	// Sem id, os processos são casados pela posição na entrada
+	if len(d.Processos) != 1 || d.Processos[0].Termino != -1 ||
+		!reflect.DeepEqual(d.SomenteEmA, []string{"x"}) || !reflect.DeepEqual(d.SomenteEmB, []string{"y"}) {
+		t.Errorf("processos %+v, só em A %v, só em B %v", d.Processos, d.SomenteEmA, d.SomenteEmB)
+	}
+	if !d.RastroIndisponivel || !d.Iguais {
+		t.Errorf("rastro indisponível %v, iguais %v", d.RastroIndisponivel, d.Iguais)
+	}
+}
+
+func TestCarregarReferencia(t *testing.T) {
+	body := ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 1, Priority: 1}}}
+	casos := []struct {
+		nome string
+		ref  ReferenciaExecucao
+	}{
+		{"id e simulação", ReferenciaExecucao{Id: "1", Simulation: &body}},
+		{"nenhum dos dois", ReferenciaExecucao{}},
+		{"histórico desativado", ReferenciaExecucao{Id: "1"}},
+		{"simulação inválida", ReferenciaExecucao{Simulation: &ContextBody{Alg: "rr"}}},
+	}
+	for _, c := range casos {
+		if _, err := c.ref.carregar(context.Background()); err == nil {
+			t.Errorf("%s: deveria falhar", c.nome)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// DiskBody é o corpo de POST /disk
+type DiskBody struct {
+	Alg          string            `json:"alg"`          // fcfs, sstf, scan, cscan, look ou clook
+	Cylinders    int               `json:"cylinders"`    // Quantidade de cilindros do disco (padrão 200)
+	Head         int               `json:"head"`         // Cilindro inicial do braço
+	Direction    string            `json:"direction"`    // Sentido inicial da varredura: "up" (padrão) ou "down"
+	SeekTime     int               `json:"seekTime"`     // Tempo para o braço andar um cilindro (padrão 1)
+	TransferTime int               `json:"transferTime"` // Tempo para atender a requisição com o braço no cilindro (padrão 1)
+	Requests     []RequisicaoDisco `json:"requests"`
+	MaxTime      int               `json:"maxTime"`   // Horizonte da simulação; não pode passar do limite do servidor
+	TimeoutMs    int               `json:"timeoutMs"` // Tempo de relógio máximo da simulação; não pode passar do limite do servidor
+}
+
+// RequisicaoDisco é uma requisição de acesso a um cilindro
+type RequisicaoDisco struct {
+	Cylinder int    `json:"cylinder"`
+	Arrival  int    `json:"arrival"`
+	Name     string `json:"name,omitempty"` // Nome opcional, usado no diagrama no lugar de R<id>
+	Id       string `json:"id,omitempty"`
+}
+
+// Requisicao é uma requisição em simulação: o processo da linha do tempo e o cilindro pedido
+type Requisicao struct {
+	*Processo
+	cilindro int
+}
+
+// Movimento é o próximo deslocamento do braço escolhido pelo algoritmo
+type Movimento struct {
+	destino    int
+	requisicao *Requisicao // Atendida ao chegar ao destino; nil quando o braço só se desloca
+	retorno    bool        // Volta ao início sem atender nada (C-SCAN e C-LOOK)
+}
+
+// EscalonadorDisco escolhe o próximo movimento do braço entre as requisições pendentes
+type EscalonadorDisco interface {
+	proximoMovimento() Movimento
+}
+
+// SimuladorDisco reaproveita o Simulador da CPU para a linha do tempo: cada requisição é um
+// processo que "executa" enquanto o braço se desloca até ela e a atende
+type SimuladorDisco struct {
+	*Simulador
+	requisicoes   []*Requisicao // Ordenadas pela chegada
+	cilindros     int
+	cabeca        int // Cilindro atual do braço
+	direcao       int // +1 no sentido dos cilindros maiores, -1 no dos menores
+	tempoBusca    int
+	transferencia int
+	passos        []PassoDisco
+}
+
+// PassoDisco descreve um deslocamento do braço
+type PassoDisco struct {
+	Inicio     int    `json:"inicio"`
+	Fim        int    `json:"fim"`
+	De         int    `json:"de"`
+	Para       int    `json:"para"`
+	Distancia  int    `json:"distancia"`
+	Tipo       string `json:"tipo"` // "atendimento", "varredura" (até o fim do disco), "retorno" ou "interrompido"
+	Requisicao string `json:"requisicao,omitempty"`
+}
+
+// ResultadoDisco traz as seções comuns do escalonamento (diagrama, métricas por requisição,
+// estatísticas e desempenho, no mesmo formato de POST /processes) e o movimento do braço
+type ResultadoDisco struct {
+	Resultado
+	Cilindros        int          `json:"cilindros"`
+	CabecaInicial    int          `json:"cabecaInicial"`
+	Passos           []PassoDisco `json:"passos"`
+	OrdemAtendimento []string     `json:"ordemAtendimento"`
+	MovimentoTotal   int          `json:"movimentoTotal"`   // Soma das distâncias percorridas pelo braço, inclusive nos retornos
+	MovimentoRetorno int          `json:"movimentoRetorno"` // Parte do movimento total gasta nos retornos do C-SCAN e do C-LOOK
+}
+
+// Padrões da simulação de disco
+const (
+	cilindrosPadrao = 200
+	maxCilindros    = 1 << 20
+)
+
+// Dependendo do tipo escolhido, cria o escalonador de disco correspondente
+func novoEscalonadorDisco(tipo string, s *SimuladorDisco) (EscalonadorDisco, error) {
+	switch tipo {
+	case "fcfs":
+		return &FCFSDisco{s}, nil
+	case "sstf":
+		return &SSTF{s}, nil
+	case "scan":
+		return &SCAN{s}, nil
+	case "cscan":
+		return &CSCAN{s}, nil
+	case "look":
+		return &LOOK{s}, nil
+	case "clook":
+		return &CLOOK{s}, nil
+	default:
+		return nil, fmt.Errorf("algoritmo de escalonamento de disco inválido")
+	}
+}
+
+// lerRequisicoes cria os processos da linha do tempo, um por requisição
+// A duração só é conhecida quando o braço escolhe a requisição, então até lá o tempo restante fica em 1
+func lerRequisicoes(body DiskBody) []*Requisicao {
+	requisicoes := make([]*Requisicao, len(body.Requests))
+	for i, r := range body.Requests {
+		nome := r.Name
+		if nome == "" && r.Id == "" {
+			nome = fmt.Sprintf("R%d", i+1)
+		}
+		requisicoes[i] = &Requisicao{
+			Processo: &Processo{
+				id:                 i + 1,
+				nome:               nome,
+				idExterno:          r.Id,
+				instanteCriacao:    r.Arrival,
+				prioridadeOriginal: r.Cylinder,
+				prioridadeAtual:    r.Cylinder,
+				tempoRestante:      1,
+				tempoInicio:        -1,
+				tempoTermino:       -1,
+				instanteAdmissao:   -1,
+			},
+			cilindro: r.Cylinder,
+		}
+	}
+
+	// Requisições que chegam juntas mantêm a ordem da entrada
+	slices.SortStableFunc(requisicoes, func(a, b *Requisicao) int {
+		return a.instanteCriacao - b.instanteCriacao
+	})
+	return requisicoes
+}
+
+// pendentes devolve as requisições que já chegaram e ainda não foram atendidas, em ordem de chegada
+// A requisição para a qual o braço está se deslocando continua pendente até o fim da transferência
+func (s *SimuladorDisco) pendentes() []*Requisicao {
+	var pendentes []*Requisicao
+	for _, r := range s.requisicoes {
+		if r.instanteCriacao <= s.tempoAtual && r.tempoTermino == -1 {
+			pendentes = append(pendentes, r)
+		}
+	}
+	return pendentes
+}
+
+// maisProxima devolve a requisição pendente mais próxima do braço no sentido informado
+// (0 = qualquer sentido); empates ficam com a que chegou primeiro
+func (s *SimuladorDisco) maisProxima(sentido int) *Requisicao {
+	var escolhida *Requisicao
+	for _, r := range s.pendentes() {
+		distancia := r.cilindro - s.cabeca
+		if sentido*distancia < 0 {
+			continue
+		}
+		if escolhida == nil || abs(distancia) < abs(escolhida.cilindro-s.cabeca) {
+			escolhida = r
+		}
+	}
+	return escolhida
+}
+
+// extremo devolve o último cilindro no sentido informado
+func (s *SimuladorDisco) extremo(sentido int) int {
+	if sentido > 0 {
+		return s.cilindros - 1
+	}
+	return 0
+}
+
+// executar atende as requisições na ordem escolhida pelo algoritmo
+// O algoritmo decide com as requisições que já chegaram e reavalia a escolha a cada cilindro
+// percorrido, para atender as que chegaram no caminho; só os retornos vão direto ao destino.
+// Sem nenhuma pendente, o braço fica parado até a próxima chegada
+func (s *SimuladorDisco) executar(alg EscalonadorDisco) {
+	var mov Movimento
+	decidido := false
+	for !s.verificarSeTerminou() {
+		if s.interrompido() {
+			return
+		}
+		if len(s.pendentes()) == 0 {
+			s.avancarTempo(nil)
+			continue
+		}
+
+		if !decidido {
+			mov = alg.proximoMovimento()
+		}
+		decidido = false
+
+		passo := PassoDisco{Inicio: s.tempoAtual, De: s.cabeca, Tipo: "varredura"}
+		if mov.retorno {
+			passo.Tipo = "retorno"
+		}
+
+		// A requisição escolhida ocupa o braço do início do deslocamento até o fim da transferência;
+		// se o braço desviar para outra no caminho, ela volta a esperar, como um processo preemptado
+		r := mov.requisicao
+		var processo *Processo
+		if r != nil {
+			executado := 0
+			if r.tempoInicio == -1 {
+				r.tempoInicio = s.tempoAtual
+			} else {
+				executado = r.duracao - r.tempoRestante
+			}
+			r.tempoRestante = abs(mov.destino-s.cabeca)*s.tempoBusca + s.transferencia
+			r.duracao = executado + r.tempoRestante
+			passo.Tipo = "atendimento"
+			passo.Requisicao = r.rotulo()
+			processo = r.Processo
+		}
+
+		sentido := 1
+		if mov.destino < s.cabeca {
+			sentido = -1
+		}
+		for s.cabeca != mov.destino && !decidido {
+			for range s.tempoBusca {
+				if s.interrompido() {
+					return
+				}
+				s.avancarTempo(processo)
+			}
+			s.cabeca += sentido
+			passo.Distancia++
+
+			if !mov.retorno && s.cabeca != mov.destino {
+				if novo := alg.proximoMovimento(); novo != mov {
+					mov = novo
+					decidido = true
+					passo.Tipo = "interrompido"
+				}
+			}
+		}
+
+		if r != nil && !decidido {
+			for range s.transferencia {
+				if s.interrompido() {
+					return
+				}
+				s.avancarTempo(processo)
+			}
+			r.tempoTermino = s.tempoAtual
+		}
+		passo.Para = s.cabeca
+		passo.Fim = s.tempoAtual
+		s.passos = append(s.passos, passo)
+	}
+}
+
+// resultado monta o resultado da simulação de disco
+func (s *SimuladorDisco) resultado(algoritmo string, cabecaInicial int) ResultadoDisco {
+	r := ResultadoDisco{
+		Resultado:        s.imprimirResultados(),
+		Cilindros:        s.cilindros,
+		CabecaInicial:    cabecaInicial,
+		Passos:           s.passos,
+		OrdemAtendimento: []string{},
+	}
+	r.Algoritmo = algoritmo
+
+	for _, p := range s.passos {
+		r.MovimentoTotal += p.Distancia
+		if p.Tipo == "retorno" {
+			r.MovimentoRetorno += p.Distancia
+		}
+		if p.Tipo == "atendimento" {
+			r.OrdemAtendimento = append(r.OrdemAtendimento, p.Requisicao)
+		}
+	}
+	return r
+}
+
+// validarDisco confere os parâmetros da simulação de disco
+func validarDisco(body DiskBody) error {
+	if body.Cylinders < 0 || body.Cylinders > maxCilindros {
+		return fmt.Errorf("cylinders deve estar entre 1 e %d", maxCilindros)
+	}
+	cilindros := body.Cylinders
+	if cilindros == 0 {
+		cilindros = cilindrosPadrao
+	}
+	if body.Head < 0 || body.Head >= cilindros {
+		return fmt.Errorf("head deve estar entre 0 e %d", cilindros-1)
+	}
+	if body.Direction != "" && body.Direction != "up" && body.Direction != "down" {
+		return fmt.Errorf("direction deve ser \"up\" ou \"down\"")
+	}
+	if body.SeekTime < 0 || body.TransferTime < 0 {
+		return fmt.Errorf("seekTime e transferTime não podem ser negativos")
+	}
+	if len(body.Requests) == 0 {
+		return fmt.Errorf("a fila de requisições está vazia")
+	}
+	if err := limites.validar(ContextBody{MaxTime: body.MaxTime, TimeoutMs: body.TimeoutMs}); err != nil {
+		return err
+	}
+	if len(body.Requests) > limites.MaxProcessos {
+		return fmt.Errorf("a fila tem %d requisições; o limite do servidor é %d", len(body.Requests), limites.MaxProcessos)
+	}
+
+	ids := map[string]int{}
+	for i, r := range body.Requests {
+		if r.Cylinder < 0 || r.Cylinder >= cilindros {
+			return fmt.Errorf("cilindro inválido na requisição %d", i+1)
+		}
+		if r.Arrival < 0 {
+			return fmt.Errorf("chegada inválida na requisição %d", i+1)
+		}
+		if r.Id != "" {
+			if anterior, ok := ids[r.Id]; ok {
+				return fmt.Errorf("Id %q repetido nas requisições %d e %d", r.Id, anterior, i+1)
+			}
+			ids[r.Id] = i + 1
+		}
+	}
+	return nil
+}
+
+// processDisk executa a simulação de escalonamento de disco
+func processDisk(ctx context.Context, body DiskBody) (ResultadoDisco, error) {
+	requisicoes := lerRequisicoes(body)
+	processos := make([]*Processo, len(requisicoes))
+	for i, r := range requisicoes {
+		processos[i] = r.Processo
+	}
+
+	s := &SimuladorDisco{
+		Simulador:     novoSimulador(processos, 0),
+		requisicoes:   requisicoes,
+		cilindros:     body.Cylinders,
+		cabeca:        body.Head,
+		direcao:       1,
+		tempoBusca:    body.SeekTime,
+		transferencia: body.TransferTime,
+	}
+	if s.cilindros == 0 {
+		s.cilindros = cilindrosPadrao
+	}
+	if body.Direction == "down" {
+		s.direcao = -1
+	}
+	if s.tempoBusca == 0 {
+		s.tempoBusca = 1
+	}
+	if s.transferencia == 0 {
+		s.transferencia = 1
+	}
+	s.horizonte = limites.horizonte(ContextBody{MaxTime: body.MaxTime})
+	s.ctx = ctx
+
+	alg, err := novoEscalonadorDisco(body.Alg, s)
+	if err != nil {
+		return ResultadoDisco{}, err
+	}
+
+	s.executar(alg)
+
+	// Uma simulação cancelada não tem resultado, nem parcial
+	if err := ctx.Err(); err != nil {
+		return ResultadoDisco{}, erroCancelamento(err)
+	}
+	return s.resultado(body.Alg, body.Head), nil
+}
+
+// abs devolve o valor absoluto de x
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
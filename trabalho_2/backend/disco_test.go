@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestEscalonamentoDeDisco(t *testing.T) {
+	// Fila clássica do livro: braço no cilindro 53 de um disco com 200 cilindros
+	fila := []int{98, 183, 37, 122, 14, 124, 65, 67}
+	requisicoes := make([]RequisicaoDisco, len(fila))
+	cilindro := map[string]int{}
+	for i, c := range fila {
+		requisicoes[i] = RequisicaoDisco{Cylinder: c}
+		cilindro[fmt.Sprintf("R%d", i+1)] = c
+	}
+
+	casos := []struct {
+		alg     string
+		direcao string
+		ordem   []int
+		total   int
+		retorno int
+	}{
+		{"fcfs", "", fila, 640, 0},
+		{"sstf", "", []int{65, 67, 37, 14, 98, 122, 124, 183}, 236, 0},
+		// O SCAN vai até a borda do disco antes de inverter: 53 -> 0 -> 183
+		{"scan", "down", []int{37, 14, 65, 67, 98, 122, 124, 183}, 236, 0},
+		{"scan", "up", []int{65, 67, 98, 122, 124, 183, 37, 14}, 331, 0},
+		// O C-SCAN vai até 199, volta a 0 sem atender nada e segue até 37
+		{"cscan", "up", []int{65, 67, 98, 122, 124, 183, 14, 37}, 382, 199},
+		// O LOOK inverte na última requisição: 53 -> 183 -> 14
+		{"look", "up", []int{65, 67, 98, 122, 124, 183, 37, 14}, 299, 0},
+		{"look", "down", []int{37, 14, 65, 67, 98, 122, 124, 183}, 208, 0},
+		// O C-LOOK volta de 183 direto para 14
+		{"clook", "up", []int{65, 67, 98, 122, 124, 183, 14, 37}, 322, 169},
+	}
+
+	for _, c := range casos {
+		body := DiskBody{Alg: c.alg, Head: 53, Direction: c.direcao, Requests: requisicoes}
+		if err := validarDisco(body); err != nil {
+			t.Fatal(err)
+		}
+		r, err := processDisk(context.Background(), body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ordem := make([]int, len(r.OrdemAtendimento))
+		for i, nome := range r.OrdemAtendimento {
+			ordem[i] = cilindro[nome]
+		}
+		if !slices.Equal(ordem, c.ordem) {
+			t.Errorf("%s %s: ordem %v, esperado %v", c.alg, c.direcao, ordem, c.ordem)
+		}
+		if r.MovimentoTotal != c.total || r.MovimentoRetorno != c.retorno {
+			t.Errorf("%s %s: movimento %d (retorno %d), esperado %d (%d)", c.alg, c.direcao, r.MovimentoTotal, r.MovimentoRetorno, c.total, c.retorno)
+		}
+	}
+}
+
+func TestTempoDoDisco(t *testing.T) {
+	// Cada cilindro percorrido custa seekTime e cada atendimento, transferTime
+	// R2 chega em t=1, com o braço a caminho de R1; a escolha é refeita a cada cilindro,
+	// e no cilindro 11 R2 (a 2) fica mais perto que R1 (a 3)
+	body := DiskBody{Alg: "sstf", Head: 10, SeekTime: 2, TransferTime: 3, Requests: []RequisicaoDisco{
+		{Cylinder: 14, Arrival: 0},
+		{Cylinder: 9, Arrival: 1},
+	}}
+	r, err := processDisk(context.Background(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passos := []PassoDisco{
+		{Inicio: 0, Fim: 2, De: 10, Para: 11, Distancia: 1, Tipo: "interrompido", Requisicao: "R1"},
+		{Inicio: 2, Fim: 9, De: 11, Para: 9, Distancia: 2, Tipo: "atendimento", Requisicao: "R2"},
+		{Inicio: 9, Fim: 22, De: 9, Para: 14, Distancia: 5, Tipo: "atendimento", Requisicao: "R1"},
+	}
+	if !slices.Equal(r.Passos, passos) {
+		t.Errorf("passos %+v, esperado %+v", r.Passos, passos)
+	}
+	if terminos := []int{r.Processos[0].Termino, r.Processos[1].Termino}; !slices.Equal(terminos, []int{22, 9}) {
+		t.Errorf("términos %v, esperado [22 9]", terminos)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import "fmt"
+
+// Estratégias de envelhecimento aceitas no campo agingStrategy
+const (
+	envelhecimentoTick    = "tick"      // A prioridade sobe a cada unidade de tempo esperando
+	envelhecimentoQuantum = "quantum"   // A prioridade sobe a cada quantum esperando (padrão do RRPE)
+	envelhecimentoLimiar  = "threshold" // A prioridade sobe uma vez, ao esperar agingThreshold unidades seguidas
+)
+
+// Envelhecimento descreve como a prioridade dos processos que esperam na fila é aumentada
+// para evitar inanição, independentemente do algoritmo de escalonamento
+type Envelhecimento struct {
+	Estrategia         string `json:"estrategia"`
+	Incremento         int    `json:"incremento"`       // Quanto a prioridade sobe a cada aplicação
+	Limiar             int    `json:"limiar,omitempty"` // Espera contínua que dispara a estratégia "threshold"
+	ResetarAoDespachar bool   `json:"resetarAoDespachar"`
+
+	ativo     bool // Há envelhecimento nesta simulação
+	porRodada bool // O algoritmo aplica o envelhecimento ao fim de cada fatia (RRPE com "quantum")
+}
+
+// algoritmosComEnvelhecimento são os algoritmos que usam a prioridade ou o tempo restante para escolher
+var algoritmosComEnvelhecimento = map[string]bool{
+	"rrpe": true, "psp": true, "pcpp": true, "sjf": true, "srtf": true,
+}
+
+// novoEnvelhecimento valida e monta a configuração de envelhecimento pedida na requisição
+// O RRPE sempre envelhece (por padrão, a cada quantum); os demais só se agingStrategy for informado
+func novoEnvelhecimento(body ContextBody) (Envelhecimento, error) {
+	e := Envelhecimento{
+		Estrategia:         body.AgingStrategy,
+		Incremento:         body.Aging,
+		Limiar:             body.AgingThreshold,
+		ResetarAoDespachar: body.AgingReset,
+	}
+
+	if e.Estrategia == "" && body.Alg != "rrpe" {
+		return e, nil
+	}
+	if !algoritmosComEnvelhecimento[body.Alg] {
+		return e, fmt.Errorf("envelhecimento não se aplica ao algoritmo %s", body.Alg)
+	}
+	if e.Estrategia == "" {
+		e.Estrategia = envelhecimentoQuantum
+	}
+
+	switch e.Estrategia {
+	case envelhecimentoTick:
+	case envelhecimentoQuantum:
+		// Nos algoritmos com quantum, a espera é medida em fatias de execução
+		e.porRodada = body.Alg == "rrpe"
+	case envelhecimentoLimiar:
+		if e.Limiar <= 0 {
+			return e, fmt.Errorf("agingThreshold deve ser maior que 0 na estratégia %s", envelhecimentoLimiar)
+		}
+	default:
+		return e, fmt.Errorf("agingStrategy inválido: use %q, %q ou %q", envelhecimentoTick, envelhecimentoQuantum, envelhecimentoLimiar)
+	}
+
+	if body.Alg != "rrpe" && e.Incremento <= 0 {
+		return e, fmt.Errorf("aging deve ser maior que 0 para usar envelhecimento")
+	}
+
+	e.ativo = true
+	return e, nil
+}
+
+// envelhecer aumenta a prioridade do processo em n, no sentido da convenção escolhida
+func (s *Simulador) envelhecer(p *Processo, n int) {
+	p.bonusEnvelhecimento += n
+	p.envelhecimentoTotal += n
+	p.aumentoMaximo = max(p.aumentoMaximo, p.bonusEnvelhecimento)
+
+	p.prioridadeAtual = s.prioridadeBase(p)
+	s.manterElevacao(p)
+}
+
+// prioridadeBase é a prioridade do processo com o envelhecimento, sem a elevação dos protocolos de recursos
+func (s *Simulador) prioridadeBase(p *Processo) int {
+	if s.regras.maiorPrimeiro {
+		return p.prioridadeOriginal + p.bonusEnvelhecimento
+	}
+	return p.prioridadeOriginal - p.bonusEnvelhecimento // Menor número = maior prioridade
+}
+
+// criteriosTempoRestante devolve o critério usado pelo SJF e pelo SRTF
+// Com envelhecimento, o bônus acumulado é descontado do tempo restante, aproximando o processo do início da fila
+func (s *Simulador) criteriosTempoRestante() []criterio {
+	if s.envelhecimento.ativo {
+		return []criterio{criterioTempoEnvelhecido}
+	}
+	return []criterio{criterioTempoRestante}
+}
+
+// restaurarPrioridade descarta o envelhecimento acumulado pelo processo
+func (s *Simulador) restaurarPrioridade(p *Processo) {
+	p.bonusEnvelhecimento = 0
+	p.prioridadeAtual = p.prioridadeOriginal
+	s.manterElevacao(p)
+}
+
+// envelhecerPorTick aplica as estratégias medidas em unidades de tempo
+// aos processos que passaram o último instante esperando na fila
+func (s *Simulador) envelhecerPorTick() {
+	e := s.envelhecimento
+	if !e.ativo || e.porRodada {
+		return
+	}
+
+	for _, p := range s.filaDeExecucao {
+		p.esperaDesdeDespacho++
+
+		switch e.Estrategia {
+		case envelhecimentoTick:
+			s.envelhecer(p, e.Incremento)
+		case envelhecimentoQuantum:
+			if p.esperaDesdeDespacho%s.quantum == 0 {
+				s.envelhecer(p, e.Incremento)
+			}
+		case envelhecimentoLimiar:
+			if p.esperaDesdeDespacho == e.Limiar {
+				s.envelhecer(p, e.Incremento)
+			}
+		}
+	}
+}
+
+// aoDespachar é chamado quando o processo ganha a CPU
+// Zera a espera contínua e, se configurado, volta à prioridade original
+func (s *Simulador) aoDespachar(p *Processo) {
+	p.esperaDesdeDespacho = 0
+	if s.envelhecimento.ativo && s.envelhecimento.ResetarAoDespachar {
+		s.restaurarPrioridade(p)
+	}
+}
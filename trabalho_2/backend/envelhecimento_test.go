@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestEnvelhecimento(t *testing.T) {
+	// L tem a menor prioridade e só ganha a CPU se envelhecer o bastante antes de um dos H
+	entrada := []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "L"},
+		{Begin: 0, Duration: 3, Priority: 5, Name: "H1"},
+		{Begin: 3, Duration: 3, Priority: 5, Name: "H2"},
+		{Begin: 6, Duration: 3, Priority: 5, Name: "H3"},
+	}
+
+	casos := []struct {
+		nome     string
+		body     ContextBody
+		termino  []int // L, H1, H2, H3
+		aumentoL int
+	}{
+		{"sem envelhecimento", ContextBody{}, []int{12, 3, 6, 9}, 0},
+		// Em t=3, L esperou 3 unidades e chegou a 1+3*2 = 7, passando H2
+		{"tick", ContextBody{AgingStrategy: envelhecimentoTick, Aging: 2}, []int{6, 3, 9, 12}, 6},
+		// O aumento único só acontece em t=4, depois do despacho de H2; em t=6, L (6) passa H3 (5)
+		{"threshold", ContextBody{AgingStrategy: envelhecimentoLimiar, Aging: 5, AgingThreshold: 4}, []int{9, 3, 6, 12}, 5},
+		// Um aumento a cada 2 unidades não basta: L fica em 4 até o fim de H3
+		{"quantum", ContextBody{Quantum: 2, AgingStrategy: envelhecimentoQuantum, Aging: 1}, []int{12, 3, 6, 9}, 4},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			body := c.body
+			body.Alg, body.Input = "psp", entrada
+			if body.Quantum == 0 {
+				body.Quantum = 1
+			}
+			r, err := processScheduler(context.Background(), body, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			termino := make([]int, len(r.Processos))
+			for i, m := range r.Processos {
+				termino[i] = m.Termino
+			}
+			if !slices.Equal(termino, c.termino) {
+				t.Errorf("términos %v, esperado %v", termino, c.termino)
+			}
+			if aumento := r.Processos[0].AumentoPrioridadeMaximo; aumento != c.aumentoL {
+				t.Errorf("aumento máximo de L %d, esperado %d", aumento, c.aumentoL)
+			}
+			if (r.Envelhecimento != nil) != (c.body.AgingStrategy != "") {
+				t.Errorf("envelhecimento no resultado: %+v", r.Envelhecimento)
+			}
+		})
+	}
+}
+
+func TestNovoEnvelhecimento(t *testing.T) {
+	casos := []struct {
+		body       ContextBody
+		ativo      bool
+		estrategia string
+		erro       bool
+	}{
+		{ContextBody{Alg: "psp"}, false, "", false},
+		{ContextBody{Alg: "rrpe", Aging: 1}, true, envelhecimentoQuantum, false},
+		{ContextBody{Alg: "sjf", AgingStrategy: envelhecimentoTick, Aging: 1}, true, envelhecimentoTick, false},
+		{ContextBody{Alg: "fcfs", AgingStrategy: envelhecimentoTick, Aging: 1}, false, "", true},
+		{ContextBody{Alg: "psp", AgingStrategy: envelhecimentoLimiar, Aging: 1}, false, "", true},
+		{ContextBody{Alg: "psp", AgingStrategy: envelhecimentoTick}, false, "", true},
+		{ContextBody{Alg: "psp", AgingStrategy: "linear", Aging: 1}, false, "", true},
+	}
+
+	for _, c := range casos {
+		e, err := novoEnvelhecimento(c.body)
+		if (err != nil) != c.erro {
+			t.Errorf("%+v: erro %v", c.body, err)
+			continue
+		}
+		if !c.erro && (e.ativo != c.ativo || (c.ativo && e.Estrategia != c.estrategia)) {
+			t.Errorf("%+v: %+v, esperado ativo=%v estratégia %q", c.body, e, c.ativo, c.estrategia)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Exportador converte o resultado de uma simulação para um formato de arquivo
+type Exportador interface {
+	exportar(r Resultado) ([]byte, error)
+	tipoConteudo() string // Content-Type usado na resposta HTTP
+	extensao() string     // Extensão sugerida para o arquivo
+}
+
+// OpcoesExportacao reúne os parâmetros opcionais aceitos pelos exportadores
+type OpcoesExportacao struct {
+	PorCPU bool   // Inclui uma trilha por CPU além das trilhas por processo
+	Tabela string // Tabela exportada em CSV: "processos" ou "diagrama"
+}
+
+// Dependendo do formato escolhido, cria o exportador correspondente
+func novoExportador(formato string, opcoes OpcoesExportacao) (Exportador, error) {
+	switch formato {
+	case "chrome", "perfetto":
+		return &ChromeTrace{opcoes.PorCPU}, nil
+	case "svg":
+		return &SVGGantt{}, nil
+	case "markdown", "md":
+		return &Markdown{}, nil
+	case "csv":
+		return &CSV{opcoes.Tabela}, nil
+	case "latex", "tikz":
+		return &LaTeX{}, nil
+	default:
+		return nil, fmt.Errorf("formato de exportação inválido")
+	}
+}
+
+// Estados que uma trilha pode assumir na linha do tempo
+const (
+	estadoExecutando    = "executando"
+	estadoEsperando     = "esperando"
+	estadoOcioso        = "ocioso"
+	estadoForaDaMemoria = "aguardando memória"
+	estadoBloqueado     = "bloqueado"
+	estadoSemaforo      = "esperando semáforo"
+	estadoES            = "em E/S"
+	estadoEsperaFilhos  = "esperando filhos"
+)
+
+// Segmento é um intervalo contínuo [Inicio, Fim) em que uma trilha permaneceu no mesmo estado
+type Segmento struct {
+	Trilha int // Índice em LinhaDoTempo.Trilhas, ou -1 para a CPU ociosa
+	Inicio int
+	Fim    int
+	Estado string
+}
+
+// LinhaDoTempo é a forma estruturada do diagrama de tempo, usada pelos exportadores
+type LinhaDoTempo struct {
+	Trilhas   []string   // Nome de cada trilha (um processo por trilha)
+	Segmentos []Segmento // Intervalos de execução, espera e ociosidade
+	Fim       int        // Instante final da simulação
+}
+
+// linhaDoTempoDoResultado agrupa as linhas do diagramaTempo em segmentos contínuos
+func linhaDoTempoDoResultado(r Resultado) LinhaDoTempo {
+	linha := LinhaDoTempo{
+		Trilhas: make([]string, len(r.OrdemProcessos)),
+		Fim:     len(r.DiagramaTempo),
+	}
+	for i, nome := range r.OrdemProcessos {
+		linha.Trilhas[i] = strings.TrimSpace(nome)
+	}
+
+	// Estado de cada processo em um instante, a partir da marcação do diagrama
+	estado := func(t, i int) string {
+		switch r.DiagramaTempo[t][i] {
+		case "##":
+			return estadoExecutando
+		case "--":
+			return estadoEsperando
+		case marcaForaDaMemoria:
+			return estadoForaDaMemoria
+		case marcaBloqueado:
+			return estadoBloqueado
+		case marcaSemaforo:
+			return estadoSemaforo
+		case marcaES:
+			return estadoES
+		case marcaEsperaFilhos:
+			return estadoEsperaFilhos
+		}
+		return ""
+	}
+
+	for i := range linha.Trilhas {
+		inicio := 0
+		for t := 1; t <= linha.Fim; t++ {
+			// Fecha o segmento quando o estado muda ou o diagrama acaba
+			if t < linha.Fim && estado(t, i) == estado(inicio, i) {
+				continue
+			}
+			if e := estado(inicio, i); e != "" {
+				linha.Segmentos = append(linha.Segmentos, Segmento{i, inicio, t, e})
+			}
+			inicio = t
+		}
+	}
+
+	// Instantes em que nenhum processo executou ficam como ociosidade da CPU
+	inicio := -1
+	for t := 0; t <= linha.Fim; t++ {
+		ocioso := t < linha.Fim && !strings.Contains(strings.Join(r.DiagramaTempo[t], ""), "##")
+		if ocioso && inicio == -1 {
+			inicio = t
+		} else if !ocioso && inicio != -1 {
+			linha.Segmentos = append(linha.Segmentos, Segmento{-1, inicio, t, estadoOcioso})
+			inicio = -1
+		}
+	}
+
+	return linha
+}
+
+// temEstado indica se alguma trilha passou pelo estado, para que as legendas só mostrem os estados usados
+func (l LinhaDoTempo) temEstado(estado string) bool {
+	for _, seg := range l.Segmentos {
+		if seg.Estado == estado {
+			return true
+		}
+	}
+	return false
+}
+
+// execucaoNaCPU devolve, em ordem de tempo, os segmentos de execução e de ociosidade da CPU
+func (l LinhaDoTempo) execucaoNaCPU() []Segmento {
+	cpu := make([]Segmento, 0)
+	for _, seg := range l.Segmentos {
+		if seg.Estado == estadoExecutando || seg.Estado == estadoOcioso {
+			cpu = append(cpu, seg)
+		}
+	}
+
+	sort.Slice(cpu, func(i, j int) bool {
+		return cpu[i].Inicio < cpu[j].Inicio
+	})
+	return cpu
+}
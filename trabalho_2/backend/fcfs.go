@@ -1,40 +1,25 @@
 package main
 
-import (
-	"slices"
-)
-
 type FCFS struct{
 	s *Simulador
 }
 
+// criteriosFCFS descreve a ordenação da fila usada pelo FCFS
+var criteriosFCFS = []criterio{criterioChegada}
+
 // adicionarProcessosNovos verifica se há processos novos chegando neste instante, se houver um novo processo, 
 // ordena os processos que não executaram ainda pelo tempo de duracao
 func(alg *FCFS) adicionarProcessosNovos(){
 	for _, p := range alg.s.processos{
 		// Se o processo chegou agora
 				
-		if p.instanteCriacao == alg.s.tempoAtual{
+		if alg.s.chegouAgora(p){
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
 		}
 	}
 
-	// Ordena a fila de execução por ordem de chegada:
-	slices.SortFunc(alg.s.filaDeExecucao , func(a, b *Processo) int{
-		if a.instanteCriacao < b.instanteCriacao{
-			return -1
-		} else if a.instanteCriacao > b.instanteCriacao{
-			return 1
-		} else{
-			if a.tempoRestante <  b.tempoRestante{
-			return -1
-			} else if a.tempoRestante >  b.tempoRestante{
-				return 1
-			} else{
-				return 0
-			}	
-		}
-	})
+	// Ordena a fila de execução por ordem de chegada (empates seguem a cadeia de desempate)
+	alg.s.ordenarFila(criteriosFCFS)
 }
 
 func (alg *FCFS) executar(){
@@ -48,20 +33,26 @@ func (alg *FCFS) executar(){
 		if len(alg.s.filaDeExecucao) == 0 && alg.s.verificarSeTerminou() {
 			break // Todos os processos foram finalizados, podemos parar
 		}
+
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
 		
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
 			// Registra tempo ocioso no diagrama
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
 
 
 		// Pega o primeiro processo da fila
+		alg.s.registrarDespacho(criteriosFCFS)
 		processoAtual := alg.s.filaDeExecucao[0]
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -79,9 +70,11 @@ func (alg *FCFS) executar(){
 
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 
 			// Durante a execução, podem chegar novos processos
@@ -92,6 +85,10 @@ func (alg *FCFS) executar(){
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 		}
 	}
 }
\ No newline at end of file
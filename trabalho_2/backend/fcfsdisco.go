@@ -0,0 +1,11 @@
+package main
+
+// FCFSDisco atende as requisições na ordem de chegada
+type FCFSDisco struct {
+	s *SimuladorDisco
+}
+
+func (alg *FCFSDisco) proximoMovimento() Movimento {
+	r := alg.s.pendentes()[0]
+	return Movimento{destino: r.cilindro, requisicao: r}
+}
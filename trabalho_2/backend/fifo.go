@@ -0,0 +1,24 @@
+package main
+
+// FIFO substitui a página que está há mais tempo na memória
+type FIFO struct {
+	s     *SimuladorPaginacao
+	carga []int // Instante em que a página de cada quadro foi carregada
+}
+
+func (alg *FIFO) escolherVitima() int {
+	vitima := 0
+	for i := range alg.carga {
+		if alg.carga[i] < alg.carga[vitima] {
+			vitima = i
+		}
+	}
+	return vitima
+}
+
+func (alg *FIFO) aoAcessar(quadro int, falta bool) {
+	// Um acerto não muda a ordem de chegada
+	if falta {
+		alg.carga[quadro] = alg.s.instante
+	}
+}
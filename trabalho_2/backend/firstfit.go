@@ -0,0 +1,19 @@
+package main
+
+// FirstFit usa a primeira lacuna, a partir do início da memória, em que a requisição cabe
+type FirstFit struct {
+	s *SimuladorAlocacao
+}
+
+func (alg *FirstFit) alocar(tamanho int) int {
+	for i, seg := range alg.s.segmentos {
+		if seg.livre() && seg.tamanho >= tamanho {
+			return alg.s.dividir(i, tamanho)
+		}
+	}
+	return -1
+}
+
+func (alg *FirstFit) liberar(i int) {
+	alg.s.juntarVizinhos(i)
+}
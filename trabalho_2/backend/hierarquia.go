@@ -0,0 +1,333 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Marcação do diagrama para os processos bloqueados em wait() esperando os filhos
+const marcaEsperaFilhos = "ww"
+
+// ForkSpec é um filho criado por fork() depois de o pai executar At unidades
+// O filho pode criar os próprios filhos e chamar wait(), como o processo da carga
+type ForkSpec struct {
+	At       int        `json:"at"`
+	Name     string     `json:"name,omitempty"` // Nome opcional; o padrão é <pai>.<posição>
+	Duration int        `json:"duration"`
+	Priority *int       `json:"priority,omitempty"` // Padrão: a prioridade do pai
+	WaitAt   []int      `json:"waitAt,omitempty"`
+	Children []ForkSpec `json:"children,omitempty"`
+}
+
+// ControleHierarquia cria os filhos durante a simulação e controla quem espera em wait()
+type ControleHierarquia struct {
+	raizes  []*Processo // Processos da carga, na ordem da entrada
+	forks   []EventoFork
+	esperas []EsperaFilhos
+}
+
+// EventoFork registra a criação de um filho
+type EventoFork struct {
+	Instante int    `json:"instante"`
+	Pai      string `json:"pai"`
+	Filho    string `json:"filho"`
+}
+
+// EsperaFilhos é um intervalo em que o processo ficou bloqueado em wait()
+type EsperaFilhos struct {
+	Processo string   `json:"processo"`
+	Filhos   []string `json:"filhos"` // Filhos vivos quando o processo chamou wait()
+	Inicio   int      `json:"inicio"`
+	Fim      int      `json:"fim"`
+	Aberto   bool     `json:"aberto,omitempty"` // O processo ainda esperava no fim da simulação
+}
+
+// NoProcesso é um processo na árvore de processos, com os filhos na ordem de criação
+type NoProcesso struct {
+	Processo          string       `json:"processo"`
+	Criacao           int          `json:"criacao"`
+	Termino           int          `json:"termino"`         // -1 = não terminou
+	Orfao             bool         `json:"orfao,omitempty"` // O pai terminou antes dele
+	TempoEsperaFilhos int          `json:"tempoEsperaFilhos,omitempty"`
+	Filhos            []NoProcesso `json:"filhos,omitempty"`
+}
+
+// ResultadoHierarquia traz a árvore de processos, os forks e as esperas em wait()
+type ResultadoHierarquia struct {
+	Arvore           []NoProcesso   `json:"arvore"`
+	Forks            []EventoFork   `json:"forks"`
+	Esperas          []EsperaFilhos `json:"esperas"`
+	ProcessosCriados int            `json:"processosCriados"` // Filhos criados por fork() durante a simulação
+}
+
+// processosDaCarga conta os processos que a carga pode criar: as entradas (ou as threads delas) e todos os filhos
+func processosDaCarga(input []Processes) int {
+	var filhos func(specs []ForkSpec) int
+	filhos = func(specs []ForkSpec) int {
+		n := len(specs)
+		for _, spec := range specs {
+			n += filhos(spec.Children)
+		}
+		return n
+	}
+
+	total := 0
+	for _, p := range input {
+		total += max(1, len(p.Threads)) + filhos(p.Children)
+	}
+	return total
+}
+
+// validarHierarquia confere os filhos e as chamadas a wait() dos processos
+func validarHierarquia(body ContextBody) error {
+	usada := false
+	for i, entrada := range body.Input {
+		if len(entrada.Children) == 0 && len(entrada.WaitAt) == 0 {
+			continue
+		}
+		usada = true
+		if len(entrada.Threads) > 0 {
+			return fmt.Errorf("Processo %d: children e waitAt não podem ser usados com threads", i+1)
+		}
+		if err := validarForks(entrada.Children, entrada.WaitAt, duracaoDaEntrada(entrada)); err != nil {
+			return fmt.Errorf("Processo %d: %v", i+1, err)
+		}
+	}
+	if usada && body.MemorySize > 0 {
+		return fmt.Errorf("children e waitAt não podem ser usados com memorySize")
+	}
+	return nil
+}
+
+// validarForks confere os filhos e os wait() de um processo com a duração informada, descendo pela árvore
+func validarForks(filhos []ForkSpec, esperas []int, duracao int) error {
+	for _, w := range esperas {
+		if w < 0 || w >= duracao {
+			return fmt.Errorf("waitAt %d deve ficar antes do fim da execução (0 a %d)", w, duracao-1)
+		}
+	}
+	for j, filho := range filhos {
+		if filho.At < 0 || filho.At > duracao {
+			return fmt.Errorf("o filho %d deve ser criado dentro da execução do pai (at de 0 a %d)", j+1, duracao)
+		}
+		if filho.Duration <= 0 {
+			return fmt.Errorf("o filho %d precisa de duration positiva", j+1)
+		}
+		if filho.Priority != nil && *filho.Priority < 0 {
+			return fmt.Errorf("o filho %d tem prioridade inválida", j+1)
+		}
+		if err := validarForks(filho.Children, filho.WaitAt, filho.Duration); err != nil {
+			return fmt.Errorf("filho %d: %v", j+1, err)
+		}
+	}
+	return nil
+}
+
+// novoControleHierarquia prepara os processos da carga que criam filhos ou chamam wait()
+// Sem filhos nem wait() não há controle
+func novoControleHierarquia(body ContextBody, processos []*Processo) *ControleHierarquia {
+	if !slices.ContainsFunc(body.Input, func(p Processes) bool { return len(p.Children) > 0 || len(p.WaitAt) > 0 }) {
+		return nil
+	}
+
+	// Os processos estão ordenados pela chegada; as raízes da árvore seguem a ordem da entrada
+	raizes := slices.Clone(processos)
+	slices.SortFunc(raizes, func(a, b *Processo) int { return a.id - b.id })
+	return &ControleHierarquia{raizes: raizes}
+}
+
+// forkarEEsperar é chamado antes de o processo executar uma unidade de tempo: cria os filhos
+// que ainda não foram criados neste ponto da execução e, se o processo chamar wait() com filhos
+// vivos, tira-o da CPU até os filhos terminarem
+// Devolve true se o processo bloqueou; o algoritmo não deve devolvê-lo à fila de prontos
+func (s *Simulador) forkarEEsperar(p *Processo) bool {
+	// Quem ainda não executou cria os filhos de at 0 aqui: a fila deste instante já foi montada
+	for _, filho := range s.forkar(p) {
+		s.filaDeExecucao = append(s.filaDeExecucao, filho)
+	}
+
+	executado := p.duracao - p.tempoRestante
+	i := slices.Index(p.waitsPendentes, executado)
+	if i == -1 {
+		return false
+	}
+	p.waitsPendentes = slices.Delete(p.waitsPendentes, i, i+1)
+
+	var vivos []string
+	for _, filho := range p.filhos {
+		if filho.tempoRestante > 0 {
+			vivos = append(vivos, filho.rotulo())
+		}
+	}
+	if len(vivos) == 0 {
+		return false // wait() sem filhos vivos volta na hora
+	}
+
+	c := s.hierarquia
+	p.esperandoFilhos = true
+	p.indiceEsperaFilhos = len(c.esperas)
+	c.esperas = append(c.esperas, EsperaFilhos{Processo: p.rotulo(), Filhos: vivos, Inicio: s.tempoAtual, Aberto: true})
+	s.foraDaCPU(p)
+	s.registrarRecurso(p, "wait", fmt.Sprintf("espera os filhos %v", vivos))
+	return true
+}
+
+// forkar cria os filhos do processo marcados para o ponto atual da execução
+// Os filhos chegam no instante atual e são devolvidos para quem precisar colocá-los na fila
+func (s *Simulador) forkar(p *Processo) []*Processo {
+	c := s.hierarquia
+	executado := p.duracao - p.tempoRestante
+
+	var criados []*Processo
+	pendentes := p.forksPendentes[:0]
+	for _, spec := range p.forksPendentes {
+		if spec.At != executado {
+			pendentes = append(pendentes, spec)
+			continue
+		}
+
+		prioridade := p.prioridadeOriginal
+		if spec.Priority != nil {
+			prioridade = *spec.Priority
+		}
+		nome := spec.Name
+		if nome == "" {
+			nome = fmt.Sprintf("%s.%d", p.rotulo(), len(p.filhos)+1)
+		}
+
+		filho := &Processo{
+			id:                 len(s.processos) + 1,
+			nome:               nome,
+			usuario:            p.usuario,
+			grupo:              p.grupo,
+			classe:             p.classe,
+			instanteCriacao:    s.tempoAtual,
+			duracao:            spec.Duration,
+			prioridadeOriginal: prioridade,
+			prioridadeAtual:    prioridade,
+			tempoRestante:      spec.Duration,
+			tempoInicio:        -1,
+			tempoTermino:       -1,
+			instanteAdmissao:   -1,
+			pai:                p,
+			forksPendentes:     slices.Clone(spec.Children),
+			waitsPendentes:     slices.Clone(spec.WaitAt),
+		}
+		// O sorteio do desempate "random" do filho deriva da semente e do id, para não repetir o do primeiro processo
+		if s.regras.Semente != nil {
+			filho.sorteio = rand.New(rand.NewSource(*s.regras.Semente + int64(filho.id))).Int()
+		}
+		s.processos = append(s.processos, filho)
+		p.filhos = append(p.filhos, filho)
+		criados = append(criados, filho)
+
+		c.forks = append(c.forks, EventoFork{Instante: s.tempoAtual, Pai: p.rotulo(), Filho: filho.rotulo()})
+		s.registrarRecurso(filho, "fork", "criado por "+p.rotulo())
+	}
+	p.forksPendentes = pendentes
+	return criados
+}
+
+// escalonarHierarquia contabiliza o instante que terminou, cria os filhos que o processo atual
+// alcançou na execução e acorda quem esperava em wait() pelo último filho vivo
+// Deve ser chamada com o relógio já avançado
+func (s *Simulador) escalonarHierarquia(processoAtual *Processo) {
+	c := s.hierarquia
+
+	for _, p := range s.processos {
+		if p.esperandoFilhos {
+			p.tempoEsperaFilhos++
+		}
+	}
+
+	if processoAtual == nil {
+		return
+	}
+
+	// Os filhos criados agora chegam no novo instante e entram na fila pelo algoritmo
+	s.forkar(processoAtual)
+
+	pai := processoAtual.pai
+	if processoAtual.tempoRestante > 0 || pai == nil || !pai.esperandoFilhos {
+		return
+	}
+	if slices.ContainsFunc(pai.filhos, func(f *Processo) bool { return f.tempoRestante > 0 }) {
+		return
+	}
+	pai.esperandoFilhos = false
+	e := &c.esperas[pai.indiceEsperaFilhos]
+	e.Fim = s.tempoAtual
+	e.Aberto = false
+	s.filaDeExecucao = append(s.filaDeExecucao, pai)
+	s.registrarRecurso(pai, "desbloqueio", "o último filho, "+processoAtual.rotulo()+", terminou")
+}
+
+// organizarArvore coloca os processos na ordem da árvore (cada pai seguido dos seus descendentes)
+// e completa as linhas do diagrama anteriores à criação dos filhos
+func (s *Simulador) organizarArvore() {
+	c := s.hierarquia
+	var ordem []*Processo
+	var visitar func(p *Processo)
+	visitar = func(p *Processo) {
+		ordem = append(ordem, p)
+		for _, filho := range p.filhos {
+			visitar(filho)
+		}
+	}
+	for _, p := range c.raizes {
+		visitar(p)
+	}
+
+	// Cada linha do diagrama tem uma coluna por processo existente naquele instante, na ordem de s.processos
+	coluna := make(map[*Processo]int, len(s.processos))
+	for i, p := range s.processos {
+		coluna[p] = i
+	}
+	for t, linha := range s.diagramaTempo {
+		nova := make([]string, len(ordem))
+		for i, p := range ordem {
+			nova[i] = "  "
+			if coluna[p] < len(linha) {
+				nova[i] = linha[coluna[p]]
+			}
+		}
+		s.diagramaTempo[t] = nova
+	}
+	s.processos = ordem
+}
+
+// resultado monta a árvore de processos ao fim da simulação
+func (c *ControleHierarquia) resultado(s *Simulador) *ResultadoHierarquia {
+	r := &ResultadoHierarquia{Forks: c.forks, Esperas: c.esperas, ProcessosCriados: len(c.forks)}
+	if r.Forks == nil {
+		r.Forks = []EventoFork{}
+	}
+	if r.Esperas == nil {
+		r.Esperas = []EsperaFilhos{}
+	}
+	for i := range r.Esperas {
+		if r.Esperas[i].Aberto {
+			r.Esperas[i].Fim = s.tempoAtual
+		}
+	}
+
+	var no func(p *Processo) NoProcesso
+	no = func(p *Processo) NoProcesso {
+		n := NoProcesso{Processo: p.rotulo(), Criacao: p.instanteCriacao, Termino: p.tempoTermino, TempoEsperaFilhos: p.tempoEsperaFilhos}
+		if p.tempoRestante > 0 {
+			n.Termino = -1
+		}
+		if pai := p.pai; pai != nil && pai.tempoRestante == 0 && (p.tempoRestante > 0 || pai.tempoTermino < p.tempoTermino) {
+			n.Orfao = true // Adotado pelo init quando o pai terminou
+		}
+		for _, filho := range p.filhos {
+			n.Filhos = append(n.Filhos, no(filho))
+		}
+		return n
+	}
+	for _, p := range c.raizes {
+		r.Arvore = append(r.Arvore, no(p))
+	}
+	return r
+}
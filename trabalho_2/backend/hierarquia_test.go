@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// simularHierarquia executa a carga e devolve as métricas de cada processo pelo rótulo
+func simularHierarquia(t *testing.T, body ContextBody) (Resultado, map[string]MetricasProcesso) {
+	t.Helper()
+	if err := validarEntrada(body); err != nil {
+		t.Fatal(err)
+	}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metricas := map[string]MetricasProcesso{}
+	for _, p := range r.Processos {
+		metricas[p.Processo] = p
+	}
+	return r, metricas
+}
+
+func TestForkNoMeioDaExecucao(t *testing.T) {
+	// O filho nasce quando o pai completa 2 unidades e, no FCFS, espera o pai terminar
+	r, m := simularHierarquia(t, ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{
+		{Begin: 0, Duration: 4, Priority: 1, Name: "P", Children: []ForkSpec{{At: 2, Duration: 2}}},
+	}})
+
+	if !slices.Equal(r.Hierarquia.Forks, []EventoFork{{Instante: 2, Pai: "P", Filho: "P.1"}}) {
+		t.Errorf("forks %+v", r.Hierarquia.Forks)
+	}
+	filho := m["P.1"]
+	if filho.Pai != "P" || filho.Chegada != 2 || filho.Inicio != 4 || filho.Termino != 6 || filho.Prioridade != 1 {
+		t.Errorf("filho %+v, esperado criado em 2 com a prioridade do pai, executando de 4 a 6", filho)
+	}
+
+	// Antes do fork a coluna do filho fica em branco; o pai termina antes dele, que fica órfão
+	if r.DiagramaTempo[1][1] != "  " || r.DiagramaTempo[2][1] != "--" {
+		t.Errorf("diagrama do filho %v", r.DiagramaTempo)
+	}
+	if arvore := r.Hierarquia.Arvore; len(arvore) != 1 || len(arvore[0].Filhos) != 1 || !arvore[0].Filhos[0].Orfao {
+		t.Errorf("árvore %+v, esperado P com o filho órfão P.1", arvore)
+	}
+}
+
+func TestWaitEsperaOUltimoFilho(t *testing.T) {
+	// P cria a (1 unidade) e b (3 unidades) e chama wait() depois de 1 unidade: a termina em 2,
+	// mas P só volta quando b termina, em 5
+	r, m := simularHierarquia(t, ContextBody{Alg: "rr", Quantum: 2, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "P", WaitAt: []int{1}, Children: []ForkSpec{
+			{At: 0, Duration: 1, Name: "a"}, {At: 1, Duration: 3, Name: "b"}}},
+	}})
+
+	esperado := []EsperaFilhos{{Processo: "P", Filhos: []string{"a", "b"}, Inicio: 1, Fim: 5}}
+	if !reflect.DeepEqual(r.Hierarquia.Esperas, esperado) {
+		t.Errorf("esperas %+v, esperado %+v", r.Hierarquia.Esperas, esperado)
+	}
+	if m["a"].Termino != 2 || m["b"].Termino != 5 {
+		t.Errorf("a terminou em %d e b em %d, esperado 2 e 5", m["a"].Termino, m["b"].Termino)
+	}
+	if p := m["P"]; p.Termino != 7 || p.TempoEsperaFilhos != 4 {
+		t.Errorf("P terminou em %d depois de esperar %d em wait(), esperado 7 e 4", p.Termino, p.TempoEsperaFilhos)
+	}
+	for instante := 1; instante < 5; instante++ {
+		if r.DiagramaTempo[instante][0] != marcaEsperaFilhos {
+			t.Errorf("P deveria aparecer em wait() no instante %d: %q", instante, r.DiagramaTempo[instante][0])
+		}
+	}
+}
+
+func TestFilhosAninhados(t *testing.T) {
+	prioridade := 5
+	r, m := simularHierarquia(t, ContextBody{Alg: "rr", Quantum: 1, Input: []Processes{
+		{Begin: 0, Duration: 2, Priority: 2, Name: "sh", WaitAt: []int{1}, Children: []ForkSpec{
+			{At: 1, Duration: 3, Name: "c", WaitAt: []int{1}, Children: []ForkSpec{{At: 0, Duration: 2, Priority: &prioridade}}}}},
+	}})
+
+	if r.Hierarquia.ProcessosCriados != 2 {
+		t.Errorf("%d processos criados, esperado 2", r.Hierarquia.ProcessosCriados)
+	}
+	neto := m["c.1"]
+	if neto.Pai != "c" || neto.Prioridade != 5 || neto.Chegada != 1 || neto.Termino != 4 {
+		t.Errorf("neto %+v, esperado filho de c com prioridade 5, criado em 1 e terminando em 4", neto)
+	}
+	if m["c"].Pai != "sh" || m["c"].Prioridade != 2 || m["c"].Termino != 6 || m["c"].TempoEsperaFilhos != 1 {
+		t.Errorf("c %+v, esperado filho de sh terminando em 6 depois de 1 unidade em wait()", m["c"])
+	}
+	if m["sh"].Termino != 7 || m["sh"].TempoEsperaFilhos != 4 {
+		t.Errorf("sh %+v, esperado terminando em 7 depois de 4 unidades em wait()", m["sh"])
+	}
+
+	// A árvore segue a criação: sh -> c -> c.1
+	arvore := r.Hierarquia.Arvore
+	if len(arvore) != 1 || arvore[0].Processo != "sh" || len(arvore[0].Filhos) != 1 ||
+		arvore[0].Filhos[0].Processo != "c" || len(arvore[0].Filhos[0].Filhos) != 1 || arvore[0].Filhos[0].Filhos[0].Processo != "c.1" {
+		t.Errorf("árvore %+v, esperado sh -> c -> c.1", arvore)
+	}
+	for _, no := range []NoProcesso{arvore[0], arvore[0].Filhos[0], arvore[0].Filhos[0].Filhos[0]} {
+		if no.Orfao {
+			t.Errorf("%s não deveria ser órfão", no.Processo)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucketExecucoes guarda as execuções indexadas pelo id, em ordem de criação
+var bucketExecucoes = []byte("execucoes")
+
+// errExecucaoNaoEncontrada indica que o id não existe no histórico
+var errExecucaoNaoEncontrada = errors.New("execução não encontrada")
+
+// Execucao é uma simulação guardada no histórico: a entrada completa e o resultado resumido
+// O resumo não tem as séries que crescem com o tempo simulado (diagrama, rastro e ocupação da memória);
+// elas são refeitas simulando a entrada de novo, que já traz a semente de um desempate aleatório
+type Execucao struct {
+	Id        string      `json:"id"`
+	CriadoEm  time.Time   `json:"criadoEm"`
+	Entrada   ContextBody `json:"entrada"`
+	Resultado Resultado   `json:"resultado"`
+}
+
+// ResumoExecucao é o que a listagem do histórico mostra de cada execução
+type ResumoExecucao struct {
+	Id               string    `json:"id"`
+	CriadoEm         time.Time `json:"criadoEm"`
+	Algoritmo        string    `json:"algoritmo"`
+	Quantum          int       `json:"quantum"`
+	Aging            int       `json:"aging"`
+	Processos        int       `json:"processos"`
+	TempoMedioVida   float64   `json:"tempoMedioVida"`
+	TempoMedioEspera float64   `json:"tempoMedioEspera"`
+	Interrompido     bool      `json:"interrompido,omitempty"`
+}
+
+// Historico guarda as execuções em um banco bbolt, em um único arquivo
+type Historico struct {
+	db     *bolt.DB
+	maximo int // Quantas execuções ficam guardadas; as mais antigas são apagadas (0 = sem limite)
+}
+
+// historico é o histórico do servidor; nil quando desativado com -historico ""
+var historico *Historico
+
+// abrirHistorico abre (ou cria) o arquivo do histórico, guardando até maximo execuções
+func abrirHistorico(caminho string, maximo int) (*Historico, error) {
+	// Sem o tempo limite, um segundo servidor usando o mesmo arquivo ficaria travado esperando
+	db, err := bolt.Open(caminho, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketExecucoes)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Historico{db: db, maximo: maximo}, nil
+}
+
+// chaveExecucao converte o id em chave de 8 bytes, que o bbolt mantém em ordem numérica
+func chaveExecucao(id string) ([]byte, bool) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return binary.BigEndian.AppendUint64(nil, n), true
+}
+
+// salvar guarda a entrada e o resumo do resultado e devolve o id atribuído
+// Passando do limite, as execuções mais antigas são apagadas
+func (h *Historico) salvar(entrada ContextBody, resultado Resultado) (string, error) {
+	// Com a semente sorteada, refazer a simulação chega ao mesmo resultado
+	if entrada.Seed == nil {
+		entrada.Seed = resultado.Regras.Semente
+	}
+
+	var id string
+	err := h.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketExecucoes)
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		id = strconv.FormatUint(seq, 10)
+		resumo := resumirResultado(resultado)
+		resumo.Execucao = id
+		dados, err := json.Marshal(Execucao{Id: id, CriadoEm: time.Now(), Entrada: entrada, Resultado: resumo})
+		if err != nil {
+			return err
+		}
+		if err := b.Put(binary.BigEndian.AppendUint64(nil, seq), dados); err != nil {
+			return err
+		}
+
+		// Os ids são sequenciais: ficam só os maximo mais recentes
+		if h.maximo <= 0 || seq <= uint64(h.maximo) {
+			return nil
+		}
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) <= seq-uint64(h.maximo); k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return id, err
+}
+
+// resumirResultado tira do resultado as séries com um valor por instante simulado
+func resumirResultado(r Resultado) Resultado {
+	r.DiagramaTempo = nil
+	r.Rastro = nil
+	if r.Memoria != nil {
+		memoria := *r.Memoria
+		memoria.GrauMultiprogramacao = nil
+		memoria.Ocupacao = nil
+		r.Memoria = &memoria
+	}
+	return r
+}
+
+// refazer simula de novo a entrada guardada, recuperando o diagrama e o rastro que o resumo não tem
+func (e Execucao) refazer(ctx context.Context) (Resultado, error) {
+	entrada := e.Entrada
+	entrada.Trace = true
+	resultado, err := processScheduler(ctx, entrada, nil)
+	if err != nil {
+		return Resultado{}, err
+	}
+	resultado.Execucao = e.Id
+	return resultado, nil
+}
+
+// buscar devolve a execução com o id informado
+func (h *Historico) buscar(id string) (Execucao, error) {
+	var execucao Execucao
+	chave, ok := chaveExecucao(id)
+	if !ok {
+		return execucao, errExecucaoNaoEncontrada
+	}
+
+	err := h.db.View(func(tx *bolt.Tx) error {
+		dados := tx.Bucket(bucketExecucoes).Get(chave)
+		if dados == nil {
+			return errExecucaoNaoEncontrada
+		}
+		return json.Unmarshal(dados, &execucao)
+	})
+	return execucao, err
+}
+
+// listar devolve o resumo das execuções mais recentes primeiro, até limite itens
+func (h *Historico) listar(limite int) ([]ResumoExecucao, error) {
+	resumos := []ResumoExecucao{}
+	err := h.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketExecucoes).Cursor()
+		for k, dados := c.Last(); k != nil && len(resumos) < limite; k, dados = c.Prev() {
+			var e Execucao
+			if err := json.Unmarshal(dados, &e); err != nil {
+				return err
+			}
+			resumos = append(resumos, ResumoExecucao{
+				Id:               e.Id,
+				CriadoEm:         e.CriadoEm,
+				Algoritmo:        e.Resultado.Algoritmo,
+				Quantum:          e.Entrada.Quantum,
+				Aging:            e.Entrada.Aging,
+				Processos:        len(e.Entrada.Input),
+				TempoMedioVida:   e.Resultado.TempoMedioVida,
+				TempoMedioEspera: e.Resultado.TempoMedioEspera,
+				Interrompido:     e.Resultado.Interrompido,
+			})
+		}
+		return nil
+	})
+	return resumos, err
+}
+
+// apagar remove a execução do histórico
+func (h *Historico) apagar(id string) error {
+	chave, ok := chaveExecucao(id)
+	if !ok {
+		return errExecucaoNaoEncontrada
+	}
+
+	return h.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketExecucoes)
+		if b.Get(chave) == nil {
+			return errExecucaoNaoEncontrada
+		}
+		return b.Delete(chave)
+	})
+}
+
+// registrarExecucao guarda a execução no histórico, se ele estiver ativo, e anota o id no resultado
+// Uma falha ao salvar não impede a resposta: a simulação em si deu certo
+func registrarExecucao(entrada ContextBody, resultado *Resultado) {
+	if historico == nil {
+		return
+	}
+
+	id, err := historico.salvar(entrada, *resultado)
+	if err != nil {
+		log.Printf("erro ao salvar no histórico: %v", err)
+		return
+	}
+	resultado.Execucao = id
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// historicoDeTeste abre um histórico temporário e o instala como o histórico do servidor
+func historicoDeTeste(t *testing.T, maximo int) *Historico {
+	t.Helper()
+	h, err := abrirHistorico(filepath.Join(t.TempDir(), "historico.db"), maximo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	historico = h
+	t.Cleanup(func() {
+		historico = nil
+		h.db.Close()
+	})
+	return h
+}
+
+func TestHistorico(t *testing.T) {
+	h := historicoDeTeste(t, 2)
+	body := ContextBody{Alg: "rr", Quantum: 2, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 1},
+		{Begin: 1, Duration: 2, Priority: 1},
+	}}
+
+	var original Resultado
+	for range 3 {
+		r, err := processScheduler(context.Background(), body, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		registrarExecucao(body, &r)
+		original = r
+	}
+	if original.Execucao != "3" || len(original.DiagramaTempo) != 5 {
+		t.Fatalf("execução %q com %d instantes; o resultado devolvido ao cliente deve ser completo", original.Execucao, len(original.DiagramaTempo))
+	}
+
+	// Só as duas mais recentes ficam guardadas
+	resumos, err := h.listar(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range resumos {
+		ids = append(ids, r.Id)
+	}
+	if !reflect.DeepEqual(ids, []string{"3", "2"}) {
+		t.Errorf("ids %v, esperado [3 2]", ids)
+	}
+	if _, err := h.buscar("1"); !errors.Is(err, errExecucaoNaoEncontrada) {
+		t.Errorf("execução 1: erro %v, esperado não encontrada", err)
+	}
+
+	// O histórico guarda o resumo; refazer recupera o diagrama
+	execucao, err := h.buscar("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execucao.Resultado.DiagramaTempo != nil || execucao.Resultado.Estatisticas != original.Estatisticas || len(execucao.Resultado.Processos) != 2 {
+		t.Errorf("resumo guardado %+v", execucao.Resultado)
+	}
+	refeito, err := execucao.refazer(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(refeito.DiagramaTempo, original.DiagramaTempo) || refeito.Execucao != "3" || len(refeito.Rastro) == 0 {
+		t.Errorf("execução refeita com diagrama %v e %d decisões, esperado %v", refeito.DiagramaTempo, len(refeito.Rastro), original.DiagramaTempo)
+	}
+
+	if err := h.apagar("3"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"3", "x"} {
+		if err := h.apagar(id); !errors.Is(err, errExecucaoNaoEncontrada) {
+			t.Errorf("apagar %s: erro %v, esperado não encontrada", id, err)
+		}
+	}
+}
+
+func TestHistoricoGuardaSemente(t *testing.T) {
+	h := historicoDeTeste(t, 0)
+	body := ContextBody{Alg: "fcfs", Quantum: 1, TieBreak: []string{"random"}, Input: []Processes{
+		{Begin: 0, Duration: 1, Priority: 1},
+		{Begin: 0, Duration: 1, Priority: 1},
+		{Begin: 0, Duration: 1, Priority: 1},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registrarExecucao(body, &r)
+
+	// Sem seed na requisição, a semente sorteada vai para a entrada guardada
+	execucao, err := h.buscar(r.Execucao)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if execucao.Entrada.Seed == nil || *execucao.Entrada.Seed != *r.Regras.Semente {
+		t.Fatalf("semente guardada %v, esperado %d", execucao.Entrada.Seed, *r.Regras.Semente)
+	}
+	refeito, err := execucao.refazer(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(refeito.DiagramaTempo, r.DiagramaTempo) {
+		t.Errorf("diagrama refeito %v, esperado %v", refeito.DiagramaTempo, r.DiagramaTempo)
+	}
+}
+
+func TestSimularCorpoNaoGuardaNoHistorico(t *testing.T) {
+	// Exportações, /gantt e upload usam simularCorpo e não entram no histórico
+	h := historicoDeTeste(t, 0)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("POST", "/export/svg", nil)
+
+	r, ok := simularCorpo(c, ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 2, Priority: 1}}})
+	if !ok {
+		t.Fatal("simulação falhou")
+	}
+	if resumos, _ := h.listar(10); len(resumos) != 0 || r.Execucao != "" {
+		t.Errorf("%d execuções guardadas, id %q", len(resumos), r.Execucao)
+	}
+}
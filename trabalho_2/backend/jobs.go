@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Estados de um job
+const (
+	jobNaFila     = "fila"
+	jobExecutando = "executando"
+	jobConcluido  = "concluido"
+	jobErro       = "erro"
+)
+
+// JobBody é o corpo de POST /jobs: uma simulação e, opcionalmente, as variações a comparar
+// Com listas de algoritmos, quanta ou agings, o job executa todas as combinações (produto cartesiano)
+type JobBody struct {
+	Simulation ContextBody `json:"simulation"`
+	Algorithms []string    `json:"algorithms"` // Compara os algoritmos sobre a mesma carga
+	Quanta     []int       `json:"quanta"`     // Varredura de quantum
+	Agings     []int       `json:"agings"`     // Varredura de aging
+}
+
+// maxVariacoesJob limita quantas simulações uma única varredura pode pedir
+const maxVariacoesJob = 100
+
+// ResultadoVariacao é o resultado de uma das combinações executadas pelo job
+type ResultadoVariacao struct {
+	Algoritmo string     `json:"algoritmo"`
+	Quantum   int        `json:"quantum"`
+	Aging     int        `json:"aging"`
+	Resultado *Resultado `json:"resultado,omitempty"`
+	Erro      string     `json:"erro,omitempty"`
+}
+
+// Job é uma simulação (ou comparação) executada em segundo plano
+type Job struct {
+	Id          string              `json:"id"`
+	Estado      string              `json:"estado"`
+	Progresso   float64             `json:"progresso"` // Porcentagem concluída, de 0 a 100
+	Total       int                 `json:"total"`     // Quantidade de simulações do job
+	Concluidas  int                 `json:"concluidas"`
+	CriadoEm    time.Time           `json:"criadoEm"`
+	IniciadoEm  *time.Time          `json:"iniciadoEm,omitempty"`
+	TerminadoEm *time.Time          `json:"terminadoEm,omitempty"`
+	Erro        string              `json:"erro,omitempty"`
+	Resultados  []ResultadoVariacao `json:"resultados,omitempty"`
+
+	variacoes []ContextBody
+	mu        sync.Mutex
+}
+
+// GerenciadorJobs guarda os jobs e distribui a execução entre um número fixo de workers
+type GerenciadorJobs struct {
+	fila      chan *Job
+	jobs      map[string]*Job
+	ordem     []string // Ids em ordem de criação, para descartar os jobs mais antigos
+	guardados int      // Quantos jobs terminados ficam disponíveis para consulta
+	mu        sync.Mutex
+}
+
+// novoGerenciadorJobs inicia os workers; a fila aceita até capacidade jobs esperando
+func novoGerenciadorJobs(workers, capacidade, guardados int) *GerenciadorJobs {
+	g := &GerenciadorJobs{
+		fila:      make(chan *Job, capacidade),
+		jobs:      map[string]*Job{},
+		guardados: guardados,
+	}
+	for range workers {
+		go g.worker()
+	}
+	return g
+}
+
+// variacoes expande o corpo do job em uma simulação por combinação, validando cada uma
+func (b JobBody) variacoes() ([]ContextBody, error) {
+	algoritmos := b.Algorithms
+	if len(algoritmos) == 0 {
+		algoritmos = []string{b.Simulation.Alg}
+	}
+	quanta := b.Quanta
+	if len(quanta) == 0 {
+		quanta = []int{b.Simulation.Quantum}
+	}
+	agings := b.Agings
+	if len(agings) == 0 {
+		agings = []int{b.Simulation.Aging}
+	}
+
+	if n := len(algoritmos) * len(quanta) * len(agings); n > maxVariacoesJob {
+		return nil, fmt.Errorf("o job pede %d simulações; o limite é %d", n, maxVariacoesJob)
+	}
+
+	var variacoes []ContextBody
+	for _, alg := range algoritmos {
+		for _, quantum := range quanta {
+			for _, aging := range agings {
+				v := b.Simulation
+				v.Alg, v.Quantum, v.Aging = alg, quantum, aging
+				if err := validarEntrada(v); err != nil {
+					return nil, fmt.Errorf("%s (quantum %d, aging %d): %v", alg, quantum, aging, err)
+				}
+				variacoes = append(variacoes, v)
+			}
+		}
+	}
+	return variacoes, nil
+}
+
+// enfileirar cria o job e o coloca na fila; falha se a fila estiver cheia
+func (g *GerenciadorJobs) enfileirar(body JobBody) (*Job, error) {
+	variacoes, err := body.variacoes()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{
+		Id:        novoIdJob(),
+		Estado:    jobNaFila,
+		Total:     len(variacoes),
+		CriadoEm:  time.Now(),
+		variacoes: variacoes,
+	}
+
+	select {
+	case g.fila <- job:
+	default:
+		return nil, errFilaCheia
+	}
+
+	g.mu.Lock()
+	g.jobs[job.Id] = job
+	g.ordem = append(g.ordem, job.Id)
+	g.descartarAntigos()
+	g.mu.Unlock()
+
+	return job, nil
+}
+
+// errFilaCheia indica que o servidor já tem jobs demais esperando
+var errFilaCheia = errors.New("a fila de jobs está cheia, tente novamente mais tarde")
+
+// descartarAntigos remove os jobs terminados mais antigos além do limite guardado
+// Deve ser chamada com g.mu travado
+func (g *GerenciadorJobs) descartarAntigos() {
+	excesso := len(g.ordem) - g.guardados
+	restantes := g.ordem[:0]
+	for _, id := range g.ordem {
+		job := g.jobs[id]
+		job.mu.Lock()
+		terminado := job.Estado == jobConcluido || job.Estado == jobErro
+		job.mu.Unlock()
+
+		if excesso > 0 && terminado {
+			delete(g.jobs, id)
+			excesso--
+			continue
+		}
+		restantes = append(restantes, id)
+	}
+	g.ordem = restantes
+}
+
+// buscar devolve uma cópia do estado atual do job
+func (g *GerenciadorJobs) buscar(id string) (*Job, bool) {
+	g.mu.Lock()
+	job, ok := g.jobs[id]
+	g.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return &Job{
+		Id:          job.Id,
+		Estado:      job.Estado,
+		Progresso:   job.Progresso,
+		Total:       job.Total,
+		Concluidas:  job.Concluidas,
+		CriadoEm:    job.CriadoEm,
+		IniciadoEm:  job.IniciadoEm,
+		TerminadoEm: job.TerminadoEm,
+		Erro:        job.Erro,
+		Resultados:  job.Resultados,
+	}, true
+}
+
+// worker executa os jobs da fila, um de cada vez
+func (g *GerenciadorJobs) worker() {
+	for job := range g.fila {
+		job.executar()
+	}
+}
+
+// executar roda as simulações do job em sequência, atualizando o progresso a cada unidade de tempo
+func (job *Job) executar() {
+	agora := time.Now()
+	job.mu.Lock()
+	job.Estado = jobExecutando
+	job.IniciadoEm = &agora
+	job.mu.Unlock()
+
+	resultados := make([]ResultadoVariacao, 0, len(job.variacoes))
+	falhas := 0
+	for i, body := range job.variacoes {
+		progresso := func(tempoAtual, estimativa int) {
+			fracao := 1.0
+			if estimativa > 0 {
+				fracao = min(float64(tempoAtual)/float64(estimativa), 1)
+			}
+			job.mu.Lock()
+			job.Progresso = 100 * (float64(i) + fracao) / float64(job.Total)
+			job.mu.Unlock()
+		}
+
+		// Cada simulação tem o seu próprio tempo limite
+		ctx, cancelar := limites.contextoComPrazo(context.Background(), body)
+		resultado, err := processScheduler(ctx, body, progresso)
+		cancelar()
+
+		variacao := ResultadoVariacao{Algoritmo: body.Alg, Quantum: body.Quantum, Aging: body.Aging}
+		if err != nil {
+			variacao.Erro = err.Error()
+			falhas++
+		} else {
+			registrarExecucao(body, &resultado)
+			variacao.Resultado = &resultado
+		}
+		resultados = append(resultados, variacao)
+
+		job.mu.Lock()
+		job.Concluidas = i + 1
+		job.Progresso = 100 * float64(i+1) / float64(job.Total)
+		job.mu.Unlock()
+	}
+
+	fim := time.Now()
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.Resultados = resultados
+	job.TerminadoEm = &fim
+	job.Estado = jobConcluido
+	if falhas == len(resultados) {
+		job.Estado = jobErro
+		job.Erro = resultados[0].Erro
+	}
+	log.Printf("job %s: %s em %v", job.Id, job.Estado, fim.Sub(agora))
+}
+
+// novoIdJob sorteia um identificador difícil de adivinhar, já que os resultados ficam públicos
+func novoIdJob() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVariacoesJob(t *testing.T) {
+	simulacao := ContextBody{Alg: "rr", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 2, Priority: 1}}}
+
+	type combinacao struct {
+		alg            string
+		quantum, aging int
+	}
+	casos := []struct {
+		nome     string
+		body     JobBody
+		esperado []combinacao
+		erro     bool
+	}{
+		{"só a simulação", JobBody{Simulation: simulacao}, []combinacao{{"rr", 1, 0}}, false},
+		{"produto cartesiano", JobBody{Simulation: simulacao, Algorithms: []string{"rr", "fcfs"}, Quanta: []int{1, 3}},
+			[]combinacao{{"rr", 1, 0}, {"rr", 3, 0}, {"fcfs", 1, 0}, {"fcfs", 3, 0}}, false},
+		{"varredura de aging", JobBody{Simulation: simulacao, Algorithms: []string{"rrpe"}, Agings: []int{1, 2}},
+			[]combinacao{{"rrpe", 1, 1}, {"rrpe", 1, 2}}, false},
+		{"variação inválida", JobBody{Simulation: simulacao, Quanta: []int{1, 0}}, nil, true},
+		{"variações demais", JobBody{Simulation: simulacao, Quanta: make([]int, 11), Agings: make([]int, 10)}, nil, true},
+	}
+
+	for _, c := range casos {
+		variacoes, err := c.body.variacoes()
+		if (err != nil) != c.erro {
+			t.Errorf("%s: erro %v", c.nome, err)
+			continue
+		}
+		var obtido []combinacao
+		for _, v := range variacoes {
+			obtido = append(obtido, combinacao{v.Alg, v.Quantum, v.Aging})
+		}
+		if !reflect.DeepEqual(obtido, c.esperado) {
+			t.Errorf("%s: %v, esperado %v", c.nome, obtido, c.esperado)
+		}
+	}
+}
+
+// esperarJob consulta o job até ele terminar
+func esperarJob(t *testing.T, g *GerenciadorJobs, id string) *Job {
+	t.Helper()
+	for range 500 {
+		job, ok := g.buscar(id)
+		if !ok {
+			t.Fatalf("job %s não encontrado", id)
+		}
+		if job.Estado == jobConcluido || job.Estado == jobErro {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s não terminou", id)
+	return nil
+}
+
+func TestExecutarJob(t *testing.T) {
+	g := novoGerenciadorJobs(1, 4, 10)
+	entrada := []Processes{{Begin: 0, Duration: 3, Priority: 1}, {Begin: 0, Duration: 1, Priority: 1}}
+
+	job, err := g.enfileirar(JobBody{Simulation: ContextBody{Quantum: 1, Input: entrada, TieBreak: []string{}}, Algorithms: []string{"fcfs", "sjf"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	job = esperarJob(t, g, job.Id)
+
+	if job.Estado != jobConcluido || job.Progresso != 100 || job.Concluidas != 2 || len(job.Resultados) != 2 {
+		t.Fatalf("job %+v", job)
+	}
+	// Sem desempate, o FCFS segue a ordem da entrada: espera 0+3 no FCFS e 1+0 no SJF
+	for i, espera := range []float64{1.5, 0.5} {
+		if r := job.Resultados[i].Resultado; r == nil || r.TempoMedioEspera != espera {
+			t.Errorf("%s: resultado %+v, esperado espera média %v", job.Resultados[i].Algoritmo, r, espera)
+		}
+	}
+
+	if _, ok := g.buscar("inexistente"); ok {
+		t.Error("job inexistente encontrado")
+	}
+}
+
+func TestJobComFalhas(t *testing.T) {
+	valida := ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 1, Priority: 1}}}
+	invalida := valida
+	invalida.Alg = "xyz"
+
+	// O job só termina com erro se todas as simulações falharem
+	casos := []struct {
+		variacoes []ContextBody
+		estado    string
+	}{
+		{[]ContextBody{invalida, valida}, jobConcluido},
+		{[]ContextBody{invalida, invalida}, jobErro},
+	}
+	for _, c := range casos {
+		job := &Job{Total: len(c.variacoes), variacoes: c.variacoes}
+		job.executar()
+		if job.Estado != c.estado || job.Resultados[0].Erro == "" || (c.estado == jobErro) != (job.Erro != "") {
+			t.Errorf("estado %s, erro %q; esperado %s", job.Estado, job.Erro, c.estado)
+		}
+	}
+}
+
+func TestFilaDeJobs(t *testing.T) {
+	// Sem workers, os jobs ficam na fila e nada termina
+	g := novoGerenciadorJobs(0, 2, 1)
+	body := JobBody{Simulation: ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 1, Priority: 1}}}}
+
+	primeiro, _ := g.enfileirar(body)
+	segundo, _ := g.enfileirar(body)
+	if _, err := g.enfileirar(body); !errors.Is(err, errFilaCheia) {
+		t.Fatalf("erro %v, esperado fila cheia", err)
+	}
+
+	// Jobs na fila nunca são descartados; os terminados mais antigos saem além do limite guardado
+	if _, ok := g.buscar(primeiro.Id); !ok {
+		t.Error("job na fila descartado")
+	}
+	primeiro.Estado = jobConcluido
+	<-g.fila
+	terceiro, err := g.enfileirar(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, esperado := range map[string]bool{primeiro.Id: false, segundo.Id: true, terceiro.Id: true} {
+		if _, ok := g.buscar(id); ok != esperado {
+			t.Errorf("job %s guardado %v, esperado %v", id, ok, esperado)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// Justica reúne as métricas que mostram se algum processo foi prejudicado pelo escalonador
+// As médias escondem a inanição: um único processo pode esperar muito sem mudar quase nada nelas
+type Justica struct {
+	IndiceJain          float64  `json:"indiceJain"`                    // Índice de Jain sobre o slowdown (1 = todos igualmente atendidos)
+	EsperaMaxima        int      `json:"esperaMaxima"`                  // Maior tempo de espera total
+	EsperaP95           int      `json:"esperaP95"`                     // Percentil 95 do tempo de espera
+	EsperaP99           int      `json:"esperaP99"`                     // Percentil 99 do tempo de espera
+	MaiorEsperaContinua int      `json:"maiorEsperaContinua"`           // Maior intervalo seguido de espera entre todos os processos
+	LimiarInanicao      int      `json:"limiarInanicao,omitempty"`      // Espera contínua acima da qual o processo é marcado
+	ProcessosEmInanicao []string `json:"processosEmInanicao,omitempty"` // Processos que esperaram além do limiar
+}
+
+// registrarEspera conta a espera contínua dos processos que chegaram e não ocuparam a CPU neste instante
+// Deve ser chamada antes de o relógio avançar, com as mesmas condições usadas no diagrama
+// Bloqueado (recurso, semáforo, E/S ou wait()) ou fora da memória, o processo não espera pela CPU
+// e a espera contínua recomeça quando ele voltar à fila
+func (s *Simulador) registrarEspera(processoAtual *Processo) {
+	for _, p := range s.processos {
+		if p == processoAtual || p.bloqueado() || s.foraDaMemoria(p) {
+			p.esperaContinua = 0
+			continue
+		}
+		if p.instanteCriacao <= s.tempoAtual && p.tempoRestante > 0 {
+			p.esperaContinua++
+			p.maiorEsperaContinua = max(p.maiorEsperaContinua, p.esperaContinua)
+		}
+	}
+}
+
+// calcularJustica calcula as métricas de justiça sobre as métricas por processo
+// e marca os processos cuja maior espera contínua passou do limiar (0 desativa a marcação)
+func calcularJustica(metricas []MetricasProcesso, limiar int) Justica {
+	j := Justica{LimiarInanicao: limiar}
+
+	var esperas []int
+	var soma, somaQuadrados float64
+	for i := range metricas {
+		m := &metricas[i]
+		j.MaiorEsperaContinua = max(j.MaiorEsperaContinua, m.MaiorEsperaContinua)
+
+		if limiar > 0 && m.MaiorEsperaContinua > limiar {
+			m.Inanicao = true
+			j.ProcessosEmInanicao = append(j.ProcessosEmInanicao, m.Processo)
+		}
+
+		if !m.Concluido {
+			continue
+		}
+		esperas = append(esperas, m.TempoEspera)
+
+		x := m.Slowdown
+		soma += x
+		somaQuadrados += x * x
+	}
+
+	if len(esperas) == 0 {
+		return j
+	}
+
+	// Índice de Jain: (Σx)² / (n·Σx²)
+	j.IndiceJain = soma * soma / (float64(len(esperas)) * somaQuadrados)
+
+	sort.Ints(esperas)
+	j.EsperaMaxima = esperas[len(esperas)-1]
+	j.EsperaP95 = percentil(esperas, 95)
+	j.EsperaP99 = percentil(esperas, 99)
+
+	return j
+}
+
+// percentil devolve o percentil p de valores já ordenados, pelo método do posto mais próximo
+func percentil(valores []int, p float64) int {
+	posto := int(math.Ceil(p / 100 * float64(len(valores))))
+	return valores[max(posto, 1)-1]
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestEsperaContinuaIgnoraBloqueios(t *testing.T) {
+	// A começa com 2 unidades de E/S e depois espera D na fila; C espera no semáforo até D executar
+	// 4 unidades e fazer V. Só conta como espera contínua o tempo na fila de prontos
+	body := ContextBody{Alg: "fcfs", Quantum: 1, Semaphores: map[string]int{"s": 0}, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "A", Program: []ProgramStep{{Op: "io", Duration: 2}, {Op: "compute", Duration: 3}}},
+		{Begin: 0, Duration: 1, Priority: 1, Name: "C", Program: []ProgramStep{{Op: "P", Semaphore: "s"}, {Op: "compute", Duration: 1}}},
+		{Begin: 0, Duration: 4, Priority: 1, Name: "D", Program: []ProgramStep{{Op: "compute", Duration: 4}, {Op: "V", Semaphore: "s"}}},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esperado := map[string]int{"A": 3, "C": 0, "D": 0}
+	for _, p := range r.Processos {
+		if p.MaiorEsperaContinua != esperado[p.Nome] {
+			t.Errorf("%s: maior espera contínua %d, esperado %d", p.Nome, p.MaiorEsperaContinua, esperado[p.Nome])
+		}
+	}
+
+	body.StarvationThreshold = 2
+	if r, err = processScheduler(context.Background(), body, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range r.Processos {
+		if p.Nome == "C" && p.Inanicao {
+			t.Errorf("C só esperou no semáforo e não deveria ser marcado em inanição (maior espera contínua %d)", p.MaiorEsperaContinua)
+		}
+	}
+}
+
+func TestCalcularJustica(t *testing.T) {
+	metricas := []MetricasProcesso{
+		{Processo: "A", Concluido: true, TempoEspera: 0, Slowdown: 1, MaiorEsperaContinua: 0},
+		{Processo: "B", Concluido: true, TempoEspera: 3, Slowdown: 2, MaiorEsperaContinua: 3},
+		// Não concluído: conta para a inanição, mas não para o índice nem para os percentis
+		{Processo: "C", Concluido: false, TempoEspera: 9, Slowdown: 0, MaiorEsperaContinua: 9},
+	}
+
+	j := calcularJustica(metricas, 2)
+
+	// Jain: (1+2)² / (2·(1²+2²)) = 9/10
+	if math.Abs(j.IndiceJain-0.9) > 1e-9 {
+		t.Errorf("índice de Jain %v, esperado 0.9", j.IndiceJain)
+	}
+	if j.EsperaMaxima != 3 || j.EsperaP95 != 3 || j.EsperaP99 != 3 || j.MaiorEsperaContinua != 9 {
+		t.Errorf("esperas %+v", j)
+	}
+	if !slices.Equal(j.ProcessosEmInanicao, []string{"B", "C"}) || metricas[0].Inanicao || !metricas[1].Inanicao {
+		t.Errorf("inanição %v, esperado [B C]", j.ProcessosEmInanicao)
+	}
+
+	// Todos atendidos igualmente
+	if j := calcularJustica([]MetricasProcesso{{Concluido: true, Slowdown: 2}, {Concluido: true, Slowdown: 2}}, 0); j.IndiceJain != 1 || j.ProcessosEmInanicao != nil {
+		t.Errorf("justiça com slowdowns iguais %+v", j)
+	}
+	if j := calcularJustica(nil, 0); j.IndiceJain != 0 || j.EsperaMaxima != 0 {
+		t.Errorf("justiça sem processos %+v", j)
+	}
+}
+
+func TestPercentil(t *testing.T) {
+	vinte := make([]int, 20)
+	for i := range vinte {
+		vinte[i] = i + 1
+	}
+
+	casos := []struct {
+		valores  []int
+		p        float64
+		esperado int
+	}{
+		{vinte, 95, 19},
+		{vinte, 99, 20},
+		{vinte, 50, 10},
+		{[]int{1, 2, 3, 4}, 50, 2},
+		{[]int{1, 2, 3, 4}, 95, 4},
+		{[]int{1, 2, 3, 4}, 0, 1},
+		{[]int{7}, 99, 7},
+	}
+	for _, c := range casos {
+		if v := percentil(c.valores, c.p); v != c.esperado {
+			t.Errorf("p%v de %v: %d, esperado %d", c.p, c.valores, v, c.esperado)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// LaTeX gera um trecho TikZ com o gráfico de Gantt, para ser incluído em um documento
+// que carregue o pacote tikz
+type LaTeX struct{}
+
+// Cores TikZ usadas para cada estado
+var coresTikZ = map[string]string{
+	estadoExecutando:    "blue!60",
+	estadoEsperando:     "yellow!40",
+	estadoOcioso:        "gray!30",
+	estadoForaDaMemoria: "violet!30",
+	estadoBloqueado:     "red!40",
+	estadoSemaforo:      "orange!50",
+	estadoES:            "green!40",
+	estadoEsperaFilhos:  "magenta!40",
+}
+
+func (e *LaTeX) tipoConteudo() string { return "application/x-tex; charset=utf-8" }
+func (e *LaTeX) extensao() string     { return "tex" }
+
+func (e *LaTeX) exportar(r Resultado) ([]byte, error) {
+	linha := linhaDoTempoDoResultado(r)
+	fim := max(linha.Fim, 1)
+
+	// Mantém o gráfico com no máximo ~16 cm de largura
+	escala := min(0.6, 16.0/float64(fim))
+
+	// Cada processo ocupa uma linha (de cima para baixo) e a CPU fica na última
+	linhaCPU := len(linha.Trilhas)
+	y := func(i int) float64 { return -float64(i) }
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%% Gantt %s (quantum %d) gerado pelo simulador de escalonamento\n", strings.ToUpper(r.Algoritmo), r.Quantum)
+	fmt.Fprintf(&b, "\\begin{tikzpicture}[x=%.3fcm, y=0.6cm]\n", escala)
+
+	for i, nome := range linha.Trilhas {
+		fmt.Fprintf(&b, "  \\node[anchor=east] at (0, %.1f) {%s};\n", y(i)-0.5, escaparLaTeX(nome))
+	}
+	fmt.Fprintf(&b, "  \\node[anchor=east] at (0, %.1f) {\\textbf{CPU}};\n", y(linhaCPU)-0.5)
+
+	for _, seg := range linha.Segmentos {
+		i := seg.Trilha
+		if i < 0 {
+			i = linhaCPU
+		}
+		fmt.Fprintf(&b, "  \\filldraw[fill=%s, draw=white] (%d, %.1f) rectangle (%d, %.1f);\n",
+			coresTikZ[seg.Estado], seg.Inicio, y(i)-0.9, seg.Fim, y(i)-0.1)
+	}
+
+	// Linha da CPU com o nome de quem executou em cada intervalo
+	for _, seg := range linha.execucaoNaCPU() {
+		if seg.Trilha < 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  \\filldraw[fill=%s, draw=white] (%d, %.1f) rectangle node[font=\\tiny, text=white] {%s} (%d, %.1f);\n",
+			coresTikZ[estadoExecutando], seg.Inicio, y(linhaCPU)-0.9, escaparLaTeX(linha.Trilhas[seg.Trilha]), seg.Fim, y(linhaCPU)-0.1)
+	}
+
+	// Eixo do tempo
+	yEixo := y(linhaCPU) - 1.2
+	passo := max(1, fim/20)
+	fmt.Fprintf(&b, "  \\draw[->] (0, %.1f) -- (%.1f, %.1f);\n", yEixo, float64(fim)+0.5, yEixo)
+	fmt.Fprintf(&b, "  \\foreach \\t in {0,%d,...,%d} \\draw (\\t, %.1f) -- ++(0, -0.15) node[below, font=\\scriptsize] {\\t};\n",
+		passo, fim-fim%passo, yEixo)
+
+	// Legenda, com deslocamentos em cm para não depender da escala do eixo
+	yLegenda := yEixo - 1.5
+	legenda := []string{estadoExecutando, estadoEsperando, estadoOcioso}
+	for _, estado := range []string{estadoForaDaMemoria, estadoBloqueado, estadoSemaforo, estadoES, estadoEsperaFilhos} {
+		if linha.temEstado(estado) {
+			legenda = append(legenda, estado)
+		}
+	}
+	for k, estado := range legenda {
+		fmt.Fprintf(&b, "  \\fill[%s] ([xshift=%.1fcm]0, %.1f) rectangle ++(0.3cm, 0.3cm);\n", coresTikZ[estado], float64(k)*3, yLegenda)
+		fmt.Fprintf(&b, "  \\node[anchor=west, font=\\scriptsize] at ([xshift=%.1fcm]0, %.1f) {%s};\n", float64(k)*3+0.4, yLegenda+0.25, estado)
+	}
+
+	b.WriteString("\\end{tikzpicture}\n")
+	return b.Bytes(), nil
+}
+
+// escaparLaTeX troca os caracteres especiais do LaTeX presentes no nome de um processo
+func escaparLaTeX(texto string) string {
+	substituto := strings.NewReplacer(
+		`\`, `\textbackslash{}`,
+		`&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`,
+		`_`, `\_`, `{`, `\{`, `}`, `\}`,
+		`~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	)
+	return substituto.Replace(texto)
+}
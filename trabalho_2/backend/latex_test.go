@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLaTeX(t *testing.T) {
+	r := resultadoDeTeste()
+	r.Algoritmo, r.Quantum = "fcfs", 1
+	r.OrdemProcessos[1] = "B_1 "
+
+	saida, err := (&LaTeX{}).exportar(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	texto := string(saida)
+
+	// 5 instantes cabem na escala máxima de 0,6 cm por unidade
+	trechos := []string{
+		"\\begin{tikzpicture}[x=0.600cm, y=0.6cm]\n",
+		"\\node[anchor=east] at (0, -1.5) {B\\_1};",
+		"\\filldraw[fill=yellow!40, draw=white] (1, -0.9) rectangle (3, -0.1);",
+		"\\filldraw[fill=gray!30, draw=white] (3, -2.9) rectangle (4, -2.1);",
+		"rectangle node[font=\\tiny, text=white] {B\\_1} (3, -2.1);",
+		"\\end{tikzpicture}\n",
+	}
+	for _, trecho := range trechos {
+		if !strings.Contains(texto, trecho) {
+			t.Errorf("TikZ sem o trecho %q:\n%s", trecho, texto)
+		}
+	}
+}
+
+func TestEscaparLaTeX(t *testing.T) {
+	casos := map[string]string{
+		"P1":        "P1",
+		"a_b & 50%": `a\_b \& 50\%`,
+		`{x}\#`:     `\{x\}\textbackslash{}\#`,
+		"~^$":       `\textasciitilde{}\textasciicircum{}\$`,
+	}
+	for entrada, esperado := range casos {
+		if saida := escaparLaTeX(entrada); saida != esperado {
+			t.Errorf("escaparLaTeX(%q) = %q, esperado %q", entrada, saida, esperado)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+// LFU substitui a página menos referenciada desde que foi carregada
+// Empates ficam com a página carregada há mais tempo
+type LFU struct {
+	s        *SimuladorPaginacao
+	contagem []int // Referências à página de cada quadro desde a carga
+	carga    []int // Instante em que a página de cada quadro foi carregada
+}
+
+func (alg *LFU) escolherVitima() int {
+	vitima := 0
+	for i := range alg.contagem {
+		if alg.contagem[i] < alg.contagem[vitima] ||
+			(alg.contagem[i] == alg.contagem[vitima] && alg.carga[i] < alg.carga[vitima]) {
+			vitima = i
+		}
+	}
+	return vitima
+}
+
+func (alg *LFU) aoAcessar(quadro int, falta bool) {
+	if falta {
+		alg.contagem[quadro] = 0
+		alg.carga[quadro] = alg.s.instante
+	}
+	alg.contagem[quadro]++
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Limites protegem o servidor compartilhado de requisições grandes demais
+// Cada unidade simulada aloca uma linha do diagrama com uma coluna por processo
+type Limites struct {
+	TempoMaximo  int           // Maior horizonte aceito, em unidades de tempo
+	MaxProcessos int           // Maior quantidade de processos por simulação
+	TempoLimite  time.Duration // Tempo de relógio máximo de uma simulação
+}
+
+// limites é a configuração do servidor, ajustada pelas flags -tempo-maximo, -max-processos e -tempo-limite
+var limites = Limites{TempoMaximo: 10000, MaxProcessos: 200, TempoLimite: 10 * time.Second}
+
+// horizonte devolve até que instante a simulação pode avançar
+// O maxTime da requisição só pode reduzir o limite do servidor
+func (l Limites) horizonte(body ContextBody) int {
+	if body.MaxTime > 0 && body.MaxTime < l.TempoMaximo {
+		return body.MaxTime
+	}
+	return l.TempoMaximo
+}
+
+// contextoComPrazo limita o tempo de relógio da simulação
+// O timeoutMs da requisição só pode reduzir o limite do servidor
+func (l Limites) contextoComPrazo(ctx context.Context, body ContextBody) (context.Context, context.CancelFunc) {
+	prazo := l.TempoLimite
+	if pedido := time.Duration(body.TimeoutMs) * time.Millisecond; pedido > 0 && (prazo <= 0 || pedido < prazo) {
+		prazo = pedido
+	}
+	if prazo <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, prazo)
+}
+
+// validar confere se a requisição cabe nos limites do servidor
+func (l Limites) validar(body ContextBody) error {
+	if body.MaxTime < 0 {
+		return fmt.Errorf("maxTime não pode ser negativo")
+	}
+	if body.TimeoutMs < 0 {
+		return fmt.Errorf("timeoutMs não pode ser negativo")
+	}
+	// Threads e filhos criados por fork() também ocupam colunas do diagrama
+	if n := processosDaCarga(body.Input); n > l.MaxProcessos {
+		return fmt.Errorf("a carga tem %d processos; o limite do servidor é %d", n, l.MaxProcessos)
+	}
+	return nil
+}
+
+// interrompido indica se a simulação chegou ao horizonte ou foi cancelada e deve parar
+// O cancelamento vem do contexto: o cliente desconectou ou o tempo limite acabou
+func (s *Simulador) interrompido() bool {
+	if s.ctx != nil && s.ctx.Err() != nil {
+		return true
+	}
+	if s.recursos != nil && s.recursos.semSaida {
+		return true // Todos os processos que faltam estão bloqueados esperando recursos
+	}
+	if s.sincronizacao != nil && s.sincronizacao.semSaida {
+		return true // Todos os processos que faltam estão bloqueados em semáforos
+	}
+	return s.horizonte > 0 && s.tempoAtual >= s.horizonte
+}
+
+// erroCancelamento traduz o motivo do cancelamento em uma mensagem para o cliente
+func erroCancelamento(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("simulação cancelada: o tempo limite foi excedido")
+	}
+	return fmt.Errorf("simulação cancelada: %v", err)
+}
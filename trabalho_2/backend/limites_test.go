@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHorizonte(t *testing.T) {
+	l := Limites{TempoMaximo: 100}
+	casos := map[int]int{0: 100, 30: 30, 100: 100, 500: 100} // O maxTime só reduz o limite do servidor
+	for maxTime, esperado := range casos {
+		if h := l.horizonte(ContextBody{MaxTime: maxTime}); h != esperado {
+			t.Errorf("maxTime %d: horizonte %d, esperado %d", maxTime, h, esperado)
+		}
+	}
+}
+
+func TestValidarLimites(t *testing.T) {
+	l := Limites{TempoMaximo: 100, MaxProcessos: 3}
+	casos := []struct {
+		nome string
+		body ContextBody
+		erro bool
+	}{
+		{"dentro do limite", ContextBody{Input: make([]Processes, 3)}, false},
+		{"processos demais", ContextBody{Input: make([]Processes, 4)}, true},
+		// Duas threads e um filho contam como três colunas do diagrama
+		{"threads e filhos", ContextBody{Input: []Processes{{Threads: make([]ThreadSpec, 2), Children: make([]ForkSpec, 1)}}}, false},
+		{"threads e filhos demais", ContextBody{Input: []Processes{{Threads: make([]ThreadSpec, 3), Children: make([]ForkSpec, 1)}}}, true},
+		{"maxTime negativo", ContextBody{MaxTime: -1}, true},
+		{"timeoutMs negativo", ContextBody{TimeoutMs: -1}, true},
+	}
+	for _, c := range casos {
+		if err := l.validar(c.body); (err != nil) != c.erro {
+			t.Errorf("%s: erro %v", c.nome, err)
+		}
+	}
+}
+
+func TestContextoComPrazo(t *testing.T) {
+	casos := []struct {
+		limite    time.Duration
+		timeoutMs int
+		esperado  time.Duration // 0 = sem prazo
+	}{
+		{10 * time.Second, 0, 10 * time.Second},
+		{10 * time.Second, 500, 500 * time.Millisecond},
+		{10 * time.Second, 60000, 10 * time.Second}, // O timeoutMs só reduz o limite do servidor
+		{0, 0, 0},
+		{0, 500, 500 * time.Millisecond},
+	}
+	for _, c := range casos {
+		antes := time.Now()
+		ctx, cancelar := Limites{TempoLimite: c.limite}.contextoComPrazo(context.Background(), ContextBody{TimeoutMs: c.timeoutMs})
+		depois := time.Now()
+		prazo, temPrazo := ctx.Deadline()
+		cancelar()
+
+		if temPrazo != (c.esperado > 0) {
+			t.Errorf("limite %v, timeoutMs %d: prazo presente %v", c.limite, c.timeoutMs, temPrazo)
+			continue
+		}
+		if temPrazo && (prazo.Before(antes.Add(c.esperado)) || prazo.After(depois.Add(c.esperado))) {
+			t.Errorf("limite %v, timeoutMs %d: prazo em %v, esperado %v", c.limite, c.timeoutMs, prazo.Sub(antes), c.esperado)
+		}
+	}
+}
+
+func TestSimulacaoParaNoHorizonte(t *testing.T) {
+	// Uma duração enorme não pode fazer o diagrama crescer além do horizonte
+	body := ContextBody{Alg: "rr", Quantum: 2, MaxTime: 10, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "A"},
+		{Begin: 0, Duration: 1000000000, Priority: 1, Name: "B"},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !r.Interrompido || r.Horizonte != 10 || len(r.DiagramaTempo) != 10 {
+		t.Fatalf("interrompido %v, horizonte %d, diagrama com %d linhas", r.Interrompido, r.Horizonte, len(r.DiagramaTempo))
+	}
+	// A termina em 5 (0-2, 4-5); B executou 7 das unidades até o horizonte
+	a, b := r.Processos[0], r.Processos[1]
+	if !a.Concluido || a.Termino != 5 {
+		t.Errorf("A: %+v", a)
+	}
+	if b.Concluido || b.TempoRestante != 1000000000-7 {
+		t.Errorf("B: concluído %v, restante %d", b.Concluido, b.TempoRestante)
+	}
+	if r.Estatisticas.Concluidos != 1 || r.Estatisticas.NaoConcluidos != 1 {
+		t.Errorf("estatísticas %+v", r.Estatisticas)
+	}
+}
+
+func TestCancelamento(t *testing.T) {
+	body := ContextBody{Alg: "rr", Quantum: 1, Input: []Processes{
+		{Begin: 0, Duration: 50, Priority: 1},
+		{Begin: 0, Duration: 50, Priority: 1},
+	}}
+
+	cancelado, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	expirado, cancelarExpirado := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelarExpirado()
+
+	casos := []struct {
+		nome string
+		ctx  context.Context
+		erro string
+	}{
+		{"cliente desconectou", cancelado, "simulação cancelada: context canceled"},
+		{"tempo limite", expirado, "simulação cancelada: o tempo limite foi excedido"},
+	}
+	for _, c := range casos {
+		if _, err := processScheduler(c.ctx, body, nil); err == nil || err.Error() != c.erro {
+			t.Errorf("%s: erro %v, esperado %q", c.nome, err, c.erro)
+		}
+		if _, err := processDisk(c.ctx, DiskBody{Alg: "fcfs", Head: 0, Requests: []RequisicaoDisco{{Cylinder: 10}}}); err == nil || err.Error() != c.erro {
+			t.Errorf("%s (disco): erro %v, esperado %q", c.nome, err, c.erro)
+		}
+	}
+
+	// Cancelada no meio, a simulação para de avançar o relógio
+	ctx, cancelar := context.WithCancel(context.Background())
+	defer cancelar()
+	ultimo := 0
+	_, err := processScheduler(ctx, body, func(tempoAtual, estimativa int) {
+		ultimo = tempoAtual
+		if tempoAtual == 10 {
+			cancelar()
+		}
+	})
+	if err == nil || ultimo != 10 {
+		t.Errorf("erro %v, último instante %d; esperado cancelamento em 10", err, ultimo)
+	}
+}
+
+func TestCancelamentoResponde503(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	c.Request = httptest.NewRequest("POST", "/processes", nil).WithContext(ctx)
+
+	body := ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 0, Duration: 5, Priority: 1}}}
+	if _, ok := simularCorpo(c, body); ok || w.Code != 503 {
+		t.Errorf("ok %v, status %d; esperado 503", ok, w.Code)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+// LOOK é o SCAN que inverte o sentido na última requisição, sem ir até o fim do disco
+type LOOK struct {
+	s *SimuladorDisco
+}
+
+func (alg *LOOK) proximoMovimento() Movimento {
+	s := alg.s
+	r := s.maisProxima(s.direcao)
+	if r == nil {
+		s.direcao = -s.direcao
+		r = s.maisProxima(s.direcao)
+	}
+	return Movimento{destino: r.cilindro, requisicao: r}
+}
@@ -0,0 +1,21 @@
+package main
+
+// LRU substitui a página usada há mais tempo
+type LRU struct {
+	s         *SimuladorPaginacao
+	ultimoUso []int // Instante da última referência à página de cada quadro
+}
+
+func (alg *LRU) escolherVitima() int {
+	vitima := 0
+	for i := range alg.ultimoUso {
+		if alg.ultimoUso[i] < alg.ultimoUso[vitima] {
+			vitima = i
+		}
+	}
+	return vitima
+}
+
+func (alg *LRU) aoAcessar(quadro int, falta bool) {
+	alg.ultimoUso[quadro] = alg.s.instante
+}
@@ -1,33 +1,125 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"sort"
-	"strconv"
+	"math"
+	"slices"
 )
 
 // Processo representa uma tarefa a ser executada
 type Processo struct {
-	id                 int // Identificador único do processo
-	instanteCriacao    int // Momento em que o processo chega ao sistema
-	duracao            int // Tempo total que o processo precisa para executar
-	prioridadeOriginal int // Prioridade estática original (menor número = maior prioridade)
-	prioridadeAtual    int // Prioridade dinâmica que muda com o envelhecimento
-	tempoRestante      int // Quanto tempo ainda falta para o processo terminar
-	tempoInicio        int // Momento em que o processo começou a executar pela primeira vez
-	tempoTermino       int // Momento em que o processo terminou completamente
-	quantunsEsperando  int // Quantos quantums o processo passou esperando desde a última execução
+	id                  int               // Identificador único do processo
+	nome                string            // Nome opcional vindo da carga de trabalho
+	idExterno           string            // Identificador estável informado pelo usuário
+	usuario             string            // Dono do processo
+	grupo               string            // Grupo do dono do processo
+	classe              string            // Classe do processo (ex: interativo, batch)
+	rotulos             map[string]string // Metadados livres, repassados aos resultados
+	instanteCriacao     int               // Momento em que o processo chega ao sistema
+	duracao             int               // Tempo total que o processo precisa para executar
+	prioridadeOriginal  int               // Prioridade estática original (o sentido segue a convenção em RegrasOrdenacao)
+	prioridadeAtual     int               // Prioridade dinâmica que muda com o envelhecimento
+	tempoRestante       int               // Quanto tempo ainda falta para o processo terminar
+	tempoInicio         int               // Momento em que o processo começou a executar pela primeira vez
+	tempoTermino        int               // Momento em que o processo terminou completamente
+	quantunsEsperando   int               // Quantos quantums o processo passou esperando desde a última execução
+	sorteio             int               // Valor sorteado usado pelo desempate "random"
+	bonusEnvelhecimento int               // Quanto a prioridade subiu por envelhecimento desde a última restauração
+	envelhecimentoTotal int               // Soma de todos os aumentos de prioridade por envelhecimento
+	aumentoMaximo       int               // Maior aumento acumulado de prioridade por envelhecimento
+	esperaDesdeDespacho int               // Unidades de tempo esperando na fila desde a última vez que executou
+	esperaContinua      int               // Unidades de tempo seguidas esperando até agora
+	maiorEsperaContinua int               // Maior intervalo seguido que o processo passou esperando
+	memoria             int               // Memória que o processo ocupa enquanto está no sistema
+	naMemoria           bool              // O processo foi admitido e não está suspenso pelo swap
+	instanteAdmissao    int               // Momento em que o escalonador de longo prazo admitiu o processo (-1 = ainda não)
+	esperaMemoriaDesde  int               // Momento em que o processo passou a esperar por memória pela última vez
+	esperaMemoria       int               // Unidades de tempo esperando por memória (fila de jobs e swap)
+	swapsOut            int               // Quantas vezes o processo foi suspenso pelo swap
+	secoesCriticas      []CriticalSection // Trechos da execução em que o processo usa um recurso compartilhado
+	bloqueadoEm         *Recurso          // Recurso que o processo espera (nil = não está bloqueado)
+	pedido              int               // Instâncias que o processo espera de bloqueadoEm
+	indiceBloqueio      int               // Posição do bloqueio atual na lista de bloqueios do controle de recursos
+	tempoBloqueado      int               // Unidades de tempo bloqueado esperando recursos
+	elevado             bool              // O protocolo de acesso aos recursos elevou a prioridade do processo
+	prioridadeElevada   int               // Prioridade dada pelo protocolo enquanto o processo detém recursos
+	abortado            bool              // O processo foi abortado para desfazer um deadlock
+	reinicios           int               // Quantas vezes o processo voltou ao início para desfazer um deadlock
+	trabalhoPerdido     int               // Unidades executadas e desfeitas pelos reinícios
+	programa            []ProgramStep     // Passos de computação, semáforos e E/S (vazio = uma única rajada de CPU)
+	passo               int               // Índice do passo atual do programa
+	feitoNoPasso        int               // Unidades já cumpridas do passo atual ("compute" ou "io")
+	esperandoSemaforo   *Semaforo         // Semáforo em que o processo está bloqueado (nil = não está)
+	indiceEspera        int               // Posição da espera atual na lista de esperas do controle de sincronização
+	emES                bool              // O processo está fora da CPU esperando E/S
+	tempoSemaforo       int               // Unidades de tempo bloqueado em semáforos
+	tempoES             int               // Unidades de tempo esperando E/S
+	threads             []*Thread         // Threads de usuário que esta entidade do kernel executa (vazio = processo sem threads)
+	threadAtual         int               // Índice da thread escolhida pelo escalonador de usuário
+	fatiaThread         int               // Unidades que a thread atual executou desde que foi escolhida
+	pai                 *Processo         // Processo que criou este por fork() (nil = processo da carga)
+	filhos              []*Processo       // Filhos criados por fork(), na ordem de criação
+	forksPendentes      []ForkSpec        // Filhos que o processo ainda vai criar
+	waitsPendentes      []int             // Pontos da execução em que o processo ainda vai chamar wait()
+	esperandoFilhos     bool              // O processo está bloqueado em wait() esperando os filhos
+	indiceEsperaFilhos  int               // Posição da espera atual na lista de esperas do controle de hierarquia
+	tempoEsperaFilhos   int               // Unidades de tempo bloqueado em wait()
 }
 
 // Simulador gerencia toda a execução do escalonamento
 type Simulador struct {
-	processos        []*Processo   // Lista de todos os processos
-	filaDeExecucao   []*Processo   // Fila de processos prontos para executar
-	quantum          int           // Tamanho do quantum (tempo que cada processo pode executar)
-	tempoAtual       int           // Relógio do simulador
-	trocasContexto   int           // Contador de trocas de contexto
-	diagramaTempo    [][]string    // Matriz para armazenar o diagrama de execução
-	processoAnterior *Processo     // Guarda o último processo que executou
+	processos        []*Processo            // Lista de todos os processos
+	filaDeExecucao   []*Processo            // Fila de processos prontos para executar
+	quantum          int                    // Tamanho do quantum (tempo que cada processo pode executar)
+	tempoAtual       int                    // Relógio do simulador
+	trocasContexto   int                    // Contador de trocas de contexto
+	diagramaTempo    [][]string             // Matriz para armazenar o diagrama de execução
+	processoAnterior *Processo              // Guarda o último processo que executou
+	rastrear         bool                   // Indica se as decisões de escalonamento devem ser registradas
+	rastro           []DecisaoEscalonamento // Explicação de cada despacho e preempção
+	regras           RegrasOrdenacao        // Convenção de prioridade e cadeia de desempate
+	envelhecimento   Envelhecimento         // Estratégia de envelhecimento aplicada aos processos em espera
+	limiarInanicao   int                    // Espera contínua acima da qual um processo é marcado como em inanição (0 desativa)
+	tempoOcupado     int                    // Unidades de tempo com algum processo na CPU
+	tempoOcioso      int                    // Unidades de tempo registradas com a CPU ociosa
+	janelaVazao      int                    // Tamanho das janelas usadas no cálculo da vazão (0 desativa)
+	casasDecimais    int                    // Casas decimais das médias apresentadas em tempoMedioVida e tempoMedioEspera
+	horizonte        int                    // Instante em que a simulação para, mesmo com processos pendentes (0 = sem limite)
+	ctx              context.Context        // Cancelado quando o cliente desconecta ou o tempo limite acaba
+	progresso        func(tempoAtual int)   // Acompanha o andamento de simulações em segundo plano
+	memoria          *ControleMemoria       // Controle de admissão pela memória (nil = sem controle)
+	recursos         *ControleRecursos      // Mutexes usados pelas seções críticas (nil = nenhum processo usa recursos)
+	sincronizacao    *ControleSincronizacao // Semáforos e E/S dos programas (nil = nenhum processo tem programa)
+	threads          *ControleThreads       // Escalonador de usuário das threads (nil = nenhum processo tem threads)
+	hierarquia       *ControleHierarquia    // fork() e wait() dos processos (nil = nenhum processo cria filhos nem espera)
+}
+
+// Resultado reúne as métricas e o diagrama devolvidos ao fim da simulação
+type Resultado struct {
+	TempoMedioVida   float64                 `json:"tempoMedioVida"`   // Arredondado para apresentação (veja Estatisticas)
+	TempoMedioEspera float64                 `json:"tempoMedioEspera"` // Arredondado para apresentação (veja Estatisticas)
+	TrocasContexto   int                     `json:"trocasContexto"`
+	DiagramaTempo    [][]string              `json:"diagramaTempo"`
+	OrdemProcessos   []string                `json:"ordemProcessos"`
+	Algoritmo        string                  `json:"algoritmo"`
+	Quantum          int                     `json:"quantum"`
+	Processos        []MetricasProcesso      `json:"processos"` // Métricas individuais, na mesma ordem de ordemProcessos
+	Regras           RegrasOrdenacao         `json:"regras"`
+	Rastro           []DecisaoEscalonamento  `json:"trace,omitempty"`
+	Envelhecimento   *Envelhecimento         `json:"envelhecimento,omitempty"` // Presente quando há envelhecimento
+	Justica          Justica                 `json:"justica"`
+	Desempenho       Desempenho              `json:"desempenho"`
+	Estatisticas     Estatisticas            `json:"estatisticas"`            // Médias exatas e contagem de processos concluídos
+	Horizonte        int                     `json:"horizonte"`               // Instante máximo que a simulação podia alcançar
+	Interrompido     bool                    `json:"interrompido,omitempty"`  // A simulação parou no horizonte; as métricas são parciais
+	Execucao         string                  `json:"execucao,omitempty"`      // Id da execução no histórico (GET /runs/:id)
+	Memoria          *ResultadoMemoria       `json:"memoria,omitempty"`       // Presente quando há controle de admissão pela memória
+	Recursos         *ResultadoRecursos      `json:"recursos,omitempty"`      // Presente quando há seções críticas
+	Sincronizacao    *ResultadoSincronizacao `json:"sincronizacao,omitempty"` // Presente quando há programas
+	Threads          *ResultadoThreads       `json:"threads,omitempty"`       // Presente quando há threads; métricas por processo e por thread
+	Hierarquia       *ResultadoHierarquia    `json:"hierarquia,omitempty"`    // Presente quando há fork() ou wait(); árvore de processos
 }
 
 type Escalonador interface{
@@ -70,12 +162,19 @@ func lerEntradas(body ContextBody) ([]*Processo, error) {
 
 		// Converte as strings para números inteiros
 		instanteCriacao:= body.Input[i].Begin
-		duracao:= body.Input[i].Duration
+		duracao:= duracaoDaEntrada(body.Input[i])
 		prioridade:= body.Input[i].Priority 
+		entrada := body.Input[i]
 
 		// Cria um novo processo com os dados lidos
 		processo := &Processo{
 			id:                 id,
+			nome:               entrada.Name,
+			idExterno:          entrada.Id,
+			usuario:            entrada.User,
+			grupo:              entrada.Group,
+			classe:             entrada.Class,
+			rotulos:            entrada.Labels,
 			instanteCriacao:    instanteCriacao,
 			duracao:            duracao,
 			prioridadeOriginal: prioridade,
@@ -84,6 +183,22 @@ func lerEntradas(body ContextBody) ([]*Processo, error) {
 			tempoInicio:        -1, // -1 indica que ainda não começou
 			quantunsEsperando:  0,
 			tempoTermino: -1,
+			memoria:            entrada.Memory,
+			secoesCriticas:     entrada.CriticalSections,
+			programa:           entrada.Program,
+			forksPendentes:     slices.Clone(entrada.Children),
+			waitsPendentes:     slices.Clone(entrada.WaitAt),
+			instanteAdmissao:   -1,
+		}
+
+		// Com threads, o processo vira as entidades do kernel do modelo escolhido
+		if len(entrada.Threads) > 0 {
+			for _, entidade := range dividirEmThreads(processo, entrada, i, body) {
+				entidade.id = id
+				processos = append(processos, entidade)
+				id++
+			}
+			continue
 		}
 		processos = append(processos, processo)
 		id++
@@ -94,7 +209,8 @@ func lerEntradas(body ContextBody) ([]*Processo, error) {
 	}
 
 	// Ordena os processos por instante de criação
-	sort.Slice(processos, func(i, j int) bool {
+	// A ordenação é estável para que processos que chegam juntos mantenham a ordem da entrada
+	sort.SliceStable(processos, func(i, j int) bool {
 		return processos[i].instanteCriacao < processos[j].instanteCriacao
 	})
 
@@ -109,28 +225,86 @@ func novoSimulador(processos []*Processo, quantum int) *Simulador {
 		quantum:        quantum,
 		tempoAtual:     0,
 		diagramaTempo:  make([][]string, 0),
+		casasDecimais:  2,
 	}
 }
 
 
 // ordenarFilaPorPrioridade ordena a fila de execução pela prioridade atual
-// Maior número = maior prioridade (prioridade 5 é mais importante que prioridade 1)
-// Em caso de empate na prioridade, mantém a ordem de chegada na fila (FIFO)
+// Por padrão, maior número = maior prioridade (prioridade 5 é mais importante que prioridade 1)
+// Em caso de empate na prioridade, aplica a cadeia de desempate e depois mantém a ordem de chegada na fila (FIFO)
 func (s *Simulador) ordenarFilaPorPrioridade() {
-	sort.SliceStable(s.filaDeExecucao, func(i, j int) bool {
-		// Ordena por prioridade atual (maior número = maior prioridade)
-		return s.filaDeExecucao[i].prioridadeAtual > s.filaDeExecucao[j].prioridadeAtual
-	})
+	s.ordenarFila([]criterio{criterioPrioridadeAtual})
 }
 
 // aplicarEnvelhecimento aumenta a prioridade dos processos que estão esperando
-// A cada quantum de espera, a prioridade aumenta em 1 (número maior = mais prioritário)
+// A cada quantum de espera, a prioridade aumenta em aging no sentido da convenção escolhida
 func (s *Simulador) aplicarEnvelhecimento(aging int) {
 	for _, p := range s.filaDeExecucao {
 		p.quantunsEsperando++
-		// A cada quantum esperando, aumenta o número da prioridade
-		p.prioridadeAtual += aging
+		// A cada quantum esperando, aumenta a prioridade
+		s.envelhecer(p, aging)
+	}
+}
+
+// avancarTempo registra o instante atual no diagrama e avança o relógio em uma unidade
+// processoAtual é o processo que ocupou a CPU neste instante (nil se a CPU ficou ociosa)
+func (s *Simulador) avancarTempo(processoAtual *Processo) {
+	s.registrarDiagrama(processoAtual)
+	s.registrarEspera(processoAtual)
+	s.registrarOcupacao(processoAtual)
+	s.tempoAtual++
+	if processoAtual != nil {
+		processoAtual.tempoRestante--
+	}
+
+	// Os processos que ficaram na fila durante este instante envelhecem
+	s.envelhecerPorTick()
+
+	// Os escalonadores de longo e médio prazo decidem quem está na memória no novo instante
+	if s.memoria != nil {
+		s.escalonarMemoria(processoAtual)
+	}
+
+	// Recursos das seções críticas concluídas são liberados e acordam quem esperava por eles
+	if s.recursos != nil {
+		s.escalonarRecursos(processoAtual)
+	}
+
+	// Os programas avançam: E/S concluídas voltam à fila e os V acordam quem esperava no semáforo
+	if s.sincronizacao != nil {
+		s.escalonarSincronizacao(processoAtual)
+	}
+
+	// O escalonador de usuário decide qual thread da entidade do kernel usou o instante
+	if s.threads != nil && processoAtual != nil && len(processoAtual.threads) > 0 {
+		s.executarThread(processoAtual)
+	}
+
+	// fork() cria os filhos durante a execução e o fim do último filho acorda o pai em wait()
+	if s.hierarquia != nil {
+		s.escalonarHierarquia(processoAtual)
+	}
+
+	if s.progresso != nil {
+		s.progresso(s.tempoAtual)
+	}
+}
+
+// estimarFim devolve um limite superior para o instante em que a simulação termina:
+// a última chegada mais a soma das durações, ou o horizonte, se vier antes
+func (s *Simulador) estimarFim() int {
+	ultimaChegada, soma := 0, 0
+	for _, p := range s.processos {
+		ultimaChegada = max(ultimaChegada, p.instanteCriacao)
+		soma += p.duracao
 	}
+
+	fim := ultimaChegada + soma
+	if s.horizonte > 0 {
+		fim = min(fim, s.horizonte)
+	}
+	return fim
 }
 
 
@@ -154,6 +328,16 @@ func (s *Simulador) registrarDiagrama(processoAtual *Processo) {
 	for i, p := range s.processos {
 		if processoAtual != nil && p.id == processoAtual.id {
 			linha[i] = "##" // Processo está executando
+		} else if p.bloqueadoEm != nil {
+			linha[i] = marcaBloqueado // Processo está bloqueado esperando um recurso
+		} else if p.esperandoSemaforo != nil {
+			linha[i] = marcaSemaforo // Processo está bloqueado em um semáforo
+		} else if p.emES {
+			linha[i] = marcaES // Processo está esperando E/S
+		} else if p.esperandoFilhos {
+			linha[i] = marcaEsperaFilhos // Processo está em wait() esperando os filhos
+		} else if s.foraDaMemoria(p) {
+			linha[i] = marcaForaDaMemoria // Processo está esperando por memória
 		} else if p.instanteCriacao <= s.tempoAtual && p.tempoRestante > 0 {
 			linha[i] = "--" // Processo está esperando
 		} else if p.tempoRestante == 0 && p.tempoTermino <= s.tempoAtual {
@@ -166,48 +350,186 @@ func (s *Simulador) registrarDiagrama(processoAtual *Processo) {
 	s.diagramaTempo = append(s.diagramaTempo, linha)
 }
 
+// Estatisticas guarda as médias exatas da simulação, sem arredondamento
+// As médias consideram apenas os processos que terminaram
+type Estatisticas struct {
+	Processos        int     `json:"processos"`
+	Concluidos       int     `json:"concluidos"`
+	NaoConcluidos    int     `json:"naoConcluidos"`
+	SomaTempoVida    int     `json:"somaTempoVida"` // Com Concluidos, permite reconstruir a média como fração exata
+	SomaTempoEspera  int     `json:"somaTempoEspera"`
+	TempoMedioVida   float64 `json:"tempoMedioVida"`
+	TempoMedioEspera float64 `json:"tempoMedioEspera"`
+}
+
 // calcularEstatisticas calcula as métricas finais do escalonamento
-func (s *Simulador) calcularEstatisticas() (float64, float64) {
-	var somaTempoVida, somaTempoEspera float64
+func calcularEstatisticas(metricas []MetricasProcesso) Estatisticas {
+	e := Estatisticas{Processos: len(metricas)}
 
-	for _, p := range s.processos {
+	for _, m := range metricas {
 		// Verifica se o processo realmente foi executado
-		if p.tempoTermino > 0 && p.tempoInicio >= 0 {
-			// Tempo de vida (turnaround) = tempo de término - instante de criação
-			tempoVida := p.tempoTermino - p.instanteCriacao
-			somaTempoVida += float64(tempoVida)
-
-			// Tempo de espera = tempo de vida - duração de execução
-			tempoEspera := tempoVida - p.duracao
-			somaTempoEspera += float64(tempoEspera)
+		if !m.Concluido {
+			e.NaoConcluidos++
+			continue
 		}
+		e.Concluidos++
+		e.SomaTempoVida += m.TempoVida
+		e.SomaTempoEspera += m.TempoEspera
 	}
 
-	
-	numProcessos := float64(len(s.processos))
+	if e.Concluidos > 0 {
+		e.TempoMedioVida = float64(e.SomaTempoVida) / float64(e.Concluidos)
+		e.TempoMedioEspera = float64(e.SomaTempoEspera) / float64(e.Concluidos)
+	}
 
-	turnAround:= fmt.Sprintf("%.2f",somaTempoVida / numProcessos)
-	wait := fmt.Sprintf("%.2f",somaTempoEspera / numProcessos)
+	return e
+}
 
-	tt, _ := strconv.ParseFloat(turnAround, 64)
-	wt, _ := strconv.ParseFloat(wait, 64)
+// arredondar arredonda x para o número de casas decimais pedido (negativo = sem arredondamento)
+func arredondar(x float64, casas int) float64 {
+	if casas < 0 {
+		return x
+	}
+	escala := math.Pow(10, float64(casas))
+	return math.Round(x*escala) / escala
+}
 
-	return tt , wt
+// MetricasProcesso guarda os tempos de um processo ao fim da simulação
+type MetricasProcesso struct {
+	Processo                string            `json:"processo"`
+	Concluido               bool              `json:"concluido"`               // O processo executou até o fim
+	TempoRestante           int               `json:"tempoRestante,omitempty"` // Quanto faltava quando a simulação foi interrompida
+	Indice                  int               `json:"indice"`                  // Posição do processo na entrada (o número de P<id>)
+	Id                      string            `json:"id,omitempty"`
+	Nome                    string            `json:"nome,omitempty"`
+	Usuario                 string            `json:"usuario,omitempty"`
+	Grupo                   string            `json:"grupo,omitempty"`
+	Classe                  string            `json:"classe,omitempty"`
+	Rotulos                 map[string]string `json:"rotulos,omitempty"`
+	Chegada                 int               `json:"chegada"`
+	Duracao                 int               `json:"duracao"`
+	Prioridade              int               `json:"prioridade"`
+	Inicio                  int               `json:"inicio"`
+	Termino                 int               `json:"termino"`
+	TempoVida               int               `json:"tempoVida"`
+	TempoEspera             int               `json:"tempoEspera"`
+	AumentoPrioridadeMaximo int               `json:"aumentoPrioridadeMaximo,omitempty"` // Maior aumento acumulado por envelhecimento
+	AumentoPrioridadeTotal  int               `json:"aumentoPrioridadeTotal,omitempty"`  // Soma de todos os aumentos por envelhecimento
+	MaiorEsperaContinua     int               `json:"maiorEsperaContinua"`               // Maior intervalo seguido esperando na fila
+	Inanicao                bool              `json:"inanicao,omitempty"`                // A espera contínua passou do limiar de inanição
+	TempoResposta           int               `json:"tempoResposta"`                     // Tempo entre a chegada e a primeira execução
+	Slowdown                float64           `json:"slowdown"`                          // Tempo de vida normalizado pela duração (1 = nunca esperou)
+	Memoria                 int               `json:"memoria,omitempty"`
+	Admissao                *int              `json:"admissao,omitempty"`          // Instante em que o processo entrou na memória pela primeira vez
+	EsperaMemoria           int               `json:"esperaMemoria,omitempty"`     // Parte da espera passada fora da memória
+	Swaps                   int               `json:"swaps,omitempty"`             // Quantas vezes o processo foi suspenso pelo swap
+	TempoBloqueado          int               `json:"tempoBloqueado,omitempty"`    // Parte da espera passada bloqueado em recursos
+	Abortado                bool              `json:"abortado,omitempty"`          // Abortado na recuperação de um deadlock
+	Reinicios               int               `json:"reinicios,omitempty"`         // Vezes que voltou ao início na recuperação de um deadlock
+	TrabalhoPerdido         int               `json:"trabalhoPerdido,omitempty"`   // CPU gasta em execuções desfeitas pelos reinícios; não conta como espera
+	TempoSemaforo           int               `json:"tempoSemaforo,omitempty"`     // Parte da espera passada bloqueado em semáforos
+	TempoES                 int               `json:"tempoES,omitempty"`           // Parte da espera passada em E/S
+	Pai                     string            `json:"pai,omitempty"`               // Processo que criou este por fork()
+	TempoEsperaFilhos       int               `json:"tempoEsperaFilhos,omitempty"` // Parte da espera passada bloqueado em wait()
+}
+
+// calcularMetricasProcessos calcula o tempo de vida e de espera de cada processo
+func (s *Simulador) calcularMetricasProcessos() []MetricasProcesso {
+	metricas := make([]MetricasProcesso, len(s.processos))
+
+	for i, p := range s.processos {
+		metricas[i] = MetricasProcesso{
+			Processo:                p.rotulo(),
+			Indice:                  p.id,
+			Id:                      p.idExterno,
+			Nome:                    p.nome,
+			Usuario:                 p.usuario,
+			Grupo:                   p.grupo,
+			Classe:                  p.classe,
+			Rotulos:                 p.rotulos,
+			Chegada:                 p.instanteCriacao,
+			Duracao:                 p.duracao,
+			Prioridade:              p.prioridadeOriginal,
+			Inicio:                  p.tempoInicio,
+			Termino:                 p.tempoTermino,
+			AumentoPrioridadeMaximo: p.aumentoMaximo,
+			AumentoPrioridadeTotal:  p.envelhecimentoTotal,
+			MaiorEsperaContinua:     p.maiorEsperaContinua,
+			TempoRestante:           p.tempoRestante,
+			Memoria:                 p.memoria,
+			EsperaMemoria:           p.esperaMemoria,
+			Swaps:                   p.swapsOut,
+			TempoBloqueado:          p.tempoBloqueado,
+			Abortado:                p.abortado,
+			Reinicios:               p.reinicios,
+			TrabalhoPerdido:         p.trabalhoPerdido,
+			TempoSemaforo:           p.tempoSemaforo,
+			TempoES:                 p.tempoES,
+			TempoEsperaFilhos:       p.tempoEsperaFilhos,
+		}
+		if p.pai != nil {
+			metricas[i].Pai = p.pai.rotulo()
+		}
+		if s.memoria != nil && p.instanteAdmissao >= 0 {
+			admissao := p.instanteAdmissao
+			metricas[i].Admissao = &admissao
+		}
+
+		// Tempo de vida (turnaround) = tempo de término - instante de criação
+		// Tempo de espera = tempo de vida - duração de execução - trabalho desfeito pelos reinícios
+		if p.tempoTermino > 0 && p.tempoInicio >= 0 {
+			metricas[i].Concluido = true
+			metricas[i].TempoVida = p.tempoTermino - p.instanteCriacao
+			metricas[i].TempoEspera = metricas[i].TempoVida - p.duracao - p.trabalhoPerdido
+			metricas[i].TempoResposta = p.tempoInicio - p.instanteCriacao
+			metricas[i].Slowdown = float64(metricas[i].TempoVida) / float64(p.duracao)
+		}
+	}
+
+	return metricas
 }
 
 // imprimirResultados exibe todos os resultados da simulação
-func (s *Simulador) imprimirResultados() (float64, float64, int, [][]string, []string){
-	tempoMedioVida, tempoMedioEspera := s.calcularEstatisticas()
+func (s *Simulador) imprimirResultados() Resultado {
 
 	ordemProcess := make([]string, len(s.processos))
 	for i, p := range s.processos {
-		ordemProcess[i] =  fmt.Sprintf("P%d ", p.id)
+		ordemProcess[i] =  p.rotulo() + " "
 	}
 
-	return tempoMedioVida, tempoMedioEspera, s.trocasContexto, s.diagramaTempo, ordemProcess
+	// As métricas de justiça marcam nas métricas por processo quem sofreu inanição
+	processos := s.calcularMetricasProcessos()
+	justica := calcularJustica(processos, s.limiarInanicao)
+	desempenho := s.calcularDesempenho(processos, s.janelaVazao)
+	estatisticas := calcularEstatisticas(processos)
+
+	return Resultado{
+		TempoMedioVida:   arredondar(estatisticas.TempoMedioVida, s.casasDecimais),
+		TempoMedioEspera: arredondar(estatisticas.TempoMedioEspera, s.casasDecimais),
+		TrocasContexto:   s.trocasContexto,
+		DiagramaTempo:    s.diagramaTempo,
+		OrdemProcessos:   ordemProcess,
+		Quantum:          s.quantum,
+		Processos:        processos,
+		Regras:           s.regras,
+		Rastro:           s.rastro,
+		Justica:          justica,
+		Desempenho:       desempenho,
+		Estatisticas:     estatisticas,
+		Horizonte:        s.horizonte,
+		Interrompido:     !s.verificarSeTerminou(),
+	}
 }
 
-func processScheduler(body ContextBody) (float64, float64, int, [][]string, []string){
+// processScheduler monta e executa a simulação descrita no corpo da requisição
+// progresso, se informado, é chamado a cada unidade de tempo simulada
+func processScheduler(ctx context.Context, body ContextBody, progresso func(tempoAtual, estimativa int)) (Resultado, error){
+
+	// Um cenário de sincronização substitui a carga pelos seus processos e semáforos
+	body, err := aplicarCenario(body)
+	if err != nil {
+		return Resultado{}, err
+	}
 
 	algoritmo := body.Alg
 	quantum := body.Quantum
@@ -216,19 +538,93 @@ func processScheduler(body ContextBody) (float64, float64, int, [][]string, []st
 	// Lê os processos do arquivo
 	processos, err := lerEntradas(body)
 	if err != nil {
-		return 0, 0, 0, nil, nil
+		return Resultado{}, err
+	}
+
+	regras, err := novasRegras(body)
+	if err != nil {
+		return Resultado{}, err
+	}
+	regras.sortearDesempate(processos)
+
+	envelhecimento, err := novoEnvelhecimento(body)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	memoria, err := novoControleMemoria(body)
+	if err != nil {
+		return Resultado{}, err
+	}
+
+	recursos, err := novoControleRecursos(body, processos, regras)
+	if err != nil {
+		return Resultado{}, err
 	}
 
 	// Cria e executa o simulador
 	simulador := novoSimulador(processos, quantum)
+	simulador.rastrear = body.Trace
+	simulador.regras = regras
+	simulador.envelhecimento = envelhecimento
+	simulador.limiarInanicao = body.StarvationThreshold
+	simulador.janelaVazao = body.ThroughputWindow
+	if body.Rounding != nil {
+		simulador.casasDecimais = *body.Rounding
+	}
+	simulador.horizonte = limites.horizonte(body)
+	simulador.ctx = ctx
+	simulador.memoria = memoria
+	simulador.recursos = recursos
+	simulador.sincronizacao = novoControleSincronizacao(body, processos)
+	simulador.threads = novoControleThreads(body, processos)
+	simulador.hierarquia = novoControleHierarquia(body, processos)
+	if progresso != nil {
+		estimativa := simulador.estimarFim()
+		simulador.progresso = func(tempoAtual int) { progresso(tempoAtual, estimativa) }
+	}
 	scheduler, err:= novoEscalonador(algoritmo, simulador, aging)
 
 	if err != nil {
-		fmt.Printf("Erro ao criar escalonador: %v\n", err)
-		return 0, 0, 0, nil,nil
+		return Resultado{}, err
 	}
 
+	// Os processos que chegam no instante 0 passam pela admissão antes do primeiro despacho
+	if memoria != nil {
+		simulador.escalonarMemoria(nil)
+	}
 	scheduler.executar()
-	return simulador.imprimirResultados()
+
+	// Uma simulação cancelada não tem resultado, nem parcial
+	if err := ctx.Err(); err != nil {
+		return Resultado{}, erroCancelamento(err)
+	}
+
+	// Com fork(), os filhos aparecem logo abaixo do pai no diagrama
+	if simulador.hierarquia != nil {
+		simulador.organizarArvore()
+	}
+
+	resultado := simulador.imprimirResultados()
+	resultado.Algoritmo = algoritmo
+	if envelhecimento.ativo {
+		resultado.Envelhecimento = &envelhecimento
+	}
+	if memoria != nil {
+		resultado.Memoria = memoria.resultado(processos)
+	}
+	if recursos != nil {
+		resultado.Recursos = recursos.resultado(simulador)
+	}
+	if simulador.sincronizacao != nil {
+		resultado.Sincronizacao = simulador.sincronizacao.resultado(simulador)
+	}
+	if simulador.threads != nil {
+		resultado.Threads = simulador.threads.resultado(simulador.casasDecimais)
+	}
+	if simulador.hierarquia != nil {
+		resultado.Hierarquia = simulador.hierarquia.resultado(simulador)
+	}
+	return resultado, nil
 }
 
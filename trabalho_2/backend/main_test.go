@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMetadadosDosProcessos(t *testing.T) {
+	// O segundo processo chega antes; os metadados precisam acompanhar a ordenação
+	body := ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{
+		{Begin: 2, Duration: 2, Priority: 1, Id: "job-7", User: "ana", Group: "lab", Class: "batch", Labels: map[string]string{"fila": "noturna"}},
+		{Begin: 0, Duration: 3, Priority: 2, Name: "editor", Class: "interativo"},
+		{Begin: 1, Duration: 1, Priority: 3},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ordem := make([]string, len(r.OrdemProcessos))
+	for i, nome := range r.OrdemProcessos {
+		ordem[i] = strings.TrimSpace(nome)
+	}
+	if esperado := []string{"editor", "P3", "job-7"}; !slices.Equal(ordem, esperado) {
+		t.Errorf("ordem %v, esperado %v", ordem, esperado)
+	}
+
+	casos := []struct {
+		processo, id, nome, usuario, grupo, classe string
+		indice, termino                            int
+		rotulos                                    map[string]string
+	}{
+		{"editor", "", "editor", "", "", "interativo", 2, 3, nil},
+		{"P3", "", "", "", "", "", 3, 4, nil},
+		{"job-7", "job-7", "", "ana", "lab", "batch", 1, 6, map[string]string{"fila": "noturna"}},
+	}
+	for i, c := range casos {
+		m := r.Processos[i]
+		if m.Processo != c.processo || m.Id != c.id || m.Nome != c.nome || m.Usuario != c.usuario || m.Grupo != c.grupo ||
+			m.Classe != c.classe || m.Indice != c.indice || m.Termino != c.termino || !maps.Equal(m.Rotulos, c.rotulos) {
+			t.Errorf("métricas %+v, esperado %+v", m, c)
+		}
+	}
+}
+
+func TestEstatisticas(t *testing.T) {
+	// FCFS: A termina em 1, B em 2 e C em 4; vidas 1+2+4 = 7 e esperas 0+1+2 = 3
+	entrada := []Processes{
+		{Begin: 0, Duration: 1, Priority: 1, Name: "A"},
+		{Begin: 0, Duration: 1, Priority: 1, Name: "B"},
+		{Begin: 0, Duration: 2, Priority: 1, Name: "C"},
+	}
+	casas := func(n int) *int { return &n }
+
+	casos := []struct {
+		nome                   string
+		rounding               *int
+		maxTime                int
+		esperado               Estatisticas
+		mediaVida, mediaEspera float64 // Valores apresentados, já arredondados
+	}{
+		{"padrão", nil, 0, Estatisticas{3, 3, 0, 7, 3, 7.0 / 3, 1}, 2.33, 1},
+		{"sem arredondamento", casas(-1), 0, Estatisticas{3, 3, 0, 7, 3, 7.0 / 3, 1}, 7.0 / 3, 1},
+		{"zero casas", casas(0), 0, Estatisticas{3, 3, 0, 7, 3, 7.0 / 3, 1}, 2, 1},
+		// Interrompida em t=3, C não conclui e fica fora das médias
+		{"interrompida", nil, 3, Estatisticas{3, 2, 1, 3, 1, 1.5, 0.5}, 1.5, 0.5},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			body := ContextBody{Alg: "fcfs", Quantum: 1, Input: entrada, Rounding: c.rounding, MaxTime: c.maxTime}
+			r, err := processScheduler(context.Background(), body, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Estatisticas != c.esperado {
+				t.Errorf("estatísticas %+v, esperado %+v", r.Estatisticas, c.esperado)
+			}
+			if r.TempoMedioVida != c.mediaVida || r.TempoMedioEspera != c.mediaEspera {
+				t.Errorf("médias apresentadas %v e %v, esperado %v e %v", r.TempoMedioVida, r.TempoMedioEspera, c.mediaVida, c.mediaEspera)
+			}
+		})
+	}
+}
+
+func TestArredondar(t *testing.T) {
+	casos := []struct {
+		x        float64
+		casas    int
+		esperado float64
+	}{
+		{2.345, 2, 2.35},
+		{2.344, 2, 2.34},
+		{2.5, 0, 3},
+		{1.0 / 3, -1, 1.0 / 3},
+		{-1.25, 1, -1.3},
+	}
+	for _, c := range casos {
+		if v := arredondar(c.x, c.casas); v != c.esperado {
+			t.Errorf("arredondar(%v, %d) = %v, esperado %v", c.x, c.casas, v, c.esperado)
+		}
+	}
+}
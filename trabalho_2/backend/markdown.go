@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Markdown gera um relatório com as métricas, a tabela de processos e o diagrama de tempo
+type Markdown struct{}
+
+func (e *Markdown) tipoConteudo() string { return "text/markdown; charset=utf-8" }
+func (e *Markdown) extensao() string     { return "md" }
+
+func (e *Markdown) exportar(r Resultado) ([]byte, error) {
+	var b bytes.Buffer
+
+	// O escalonamento de disco não tem quantum
+	if r.Quantum > 0 {
+		fmt.Fprintf(&b, "# Escalonamento %s (quantum %d)\n\n", strings.ToUpper(r.Algoritmo), r.Quantum)
+	} else {
+		fmt.Fprintf(&b, "# Escalonamento %s\n\n", strings.ToUpper(r.Algoritmo))
+	}
+	if r.Interrompido {
+		fmt.Fprintf(&b, "> Simulação interrompida no horizonte t=%d: %d de %d processos não terminaram e as métricas são parciais.\n\n",
+			r.Horizonte, r.Estatisticas.NaoConcluidos, r.Estatisticas.Processos)
+	}
+
+	b.WriteString("## Métricas\n\n")
+	b.WriteString("| Métrica | Valor |\n|---|---:|\n")
+	fmt.Fprintf(&b, "| Processos concluídos | %d de %d |\n", r.Estatisticas.Concluidos, r.Estatisticas.Processos)
+	fmt.Fprintf(&b, "| Tempo médio de vida (turnaround) | %.2f |\n", r.TempoMedioVida)
+	fmt.Fprintf(&b, "| Tempo médio de espera | %.2f |\n", r.TempoMedioEspera)
+	fmt.Fprintf(&b, "| Trocas de contexto | %d |\n", r.TrocasContexto)
+	fmt.Fprintf(&b, "| Tempo médio de resposta | %.2f |\n", r.Desempenho.TempoMedioResposta)
+	fmt.Fprintf(&b, "| Slowdown médio / máximo | %.2f / %.2f |\n", r.Desempenho.SlowdownMedio, r.Desempenho.SlowdownMaximo)
+	fmt.Fprintf(&b, "| Makespan | %d |\n", r.Desempenho.Makespan)
+	fmt.Fprintf(&b, "| Utilização da CPU | %.2f%% (%d ocupado, %d ocioso) |\n",
+		100*r.Desempenho.UtilizacaoCPU, r.Desempenho.TempoOcupado, r.Desempenho.TempoOcioso)
+	fmt.Fprintf(&b, "| Vazão (processos por unidade de tempo) | %.4f |\n", r.Desempenho.Vazao)
+	fmt.Fprintf(&b, "| Índice de justiça de Jain (slowdown) | %.4f |\n", r.Justica.IndiceJain)
+	fmt.Fprintf(&b, "| Espera máxima | %d |\n", r.Justica.EsperaMaxima)
+	fmt.Fprintf(&b, "| Espera p95 / p99 | %d / %d |\n", r.Justica.EsperaP95, r.Justica.EsperaP99)
+	fmt.Fprintf(&b, "| Maior espera contínua | %d |\n", r.Justica.MaiorEsperaContinua)
+	if r.Justica.LimiarInanicao > 0 {
+		emInanicao := "nenhum"
+		if len(r.Justica.ProcessosEmInanicao) > 0 {
+			emInanicao = escaparMarkdown(strings.Join(r.Justica.ProcessosEmInanicao, ", "))
+		}
+		fmt.Fprintf(&b, "| Processos em inanição (espera contínua > %d) | %s |\n", r.Justica.LimiarInanicao, emInanicao)
+	}
+	if m := r.Memoria; m != nil {
+		fmt.Fprintf(&b, "| Memória (admissão %s) | %d |\n", m.Admissao, m.Total)
+		fmt.Fprintf(&b, "| Grau de multiprogramação médio / máximo | %.2f / %d |\n", m.GrauMultiprogramacaoMedio, m.GrauMultiprogramacaoMaximo)
+		fmt.Fprintf(&b, "| Espera média por memória | %.2f |\n", m.EsperaMemoriaMedia)
+		if m.Swapping {
+			fmt.Fprintf(&b, "| Swaps (out / in) | %d / %d |\n", m.SwapsOut, m.SwapsIn)
+		}
+	}
+	if rec := r.Recursos; rec != nil {
+		fmt.Fprintf(&b, "| Tempo bloqueado em recursos (protocolo %s) | %d |\n", rec.Protocolo, rec.TempoBloqueioTotal)
+		fmt.Fprintf(&b, "| Inversões de prioridade | %d (%d unidades) |\n", len(rec.Inversoes), rec.TempoInversaoTotal)
+		if len(rec.Deadlocks) > 0 {
+			fmt.Fprintf(&b, "| Deadlocks (recuperação %s) | %d |\n", rec.Recuperacao, len(rec.Deadlocks))
+		}
+	}
+	if sinc := r.Sincronizacao; sinc != nil {
+		fmt.Fprintf(&b, "| Tempo bloqueado em semáforos | %d |\n", sinc.TempoEsperaTotal)
+		fmt.Fprintf(&b, "| Tempo em E/S | %d |\n", sinc.TempoESTotal)
+		if sinc.Deadlock {
+			b.WriteString("| Deadlock | todos os processos restantes bloqueados em semáforos |\n")
+		}
+	}
+	b.WriteString("\n")
+
+	if len(r.Desempenho.VazaoPorJanela) > 0 {
+		fmt.Fprintf(&b, "## Vazão em janelas de %d\n\n", r.Desempenho.JanelaVazao)
+		b.WriteString("| Janela | Concluídos | Vazão |\n|---|---:|---:|\n")
+		for _, j := range r.Desempenho.VazaoPorJanela {
+			fmt.Fprintf(&b, "| %d-%d | %d | %.4f |\n", j.Inicio, j.Fim, j.Concluidos, j.Vazao)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Processos\n\n")
+	b.WriteString("| Processo | Chegada | Duração | Prioridade | Início | Término | Tempo de vida | Tempo de espera | Resposta | Slowdown | Maior espera contínua |\n")
+	b.WriteString("|---|---:|---:|---:|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, p := range r.Processos {
+		nome := escaparMarkdown(p.Processo)
+		if p.Inanicao {
+			nome += " (inanição)"
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d | %d | %d | %d | %.2f | %d |\n",
+			nome, p.Chegada, p.Duracao, p.Prioridade, p.Inicio, p.Termino, p.TempoVida, p.TempoEspera,
+			p.TempoResposta, p.Slowdown, p.MaiorEsperaContinua)
+	}
+	b.WriteString("\n")
+
+	if h := r.Hierarquia; h != nil {
+		b.WriteString("## Árvore de processos\n\n")
+		var escrever func(nos []NoProcesso, nivel int)
+		escrever = func(nos []NoProcesso, nivel int) {
+			for _, no := range nos {
+				fmt.Fprintf(&b, "%s- %s (criado em %d", strings.Repeat("  ", nivel), escaparMarkdown(no.Processo), no.Criacao)
+				if no.Termino >= 0 {
+					fmt.Fprintf(&b, ", terminou em %d", no.Termino)
+				}
+				if no.TempoEsperaFilhos > 0 {
+					fmt.Fprintf(&b, ", %d em wait()", no.TempoEsperaFilhos)
+				}
+				if no.Orfao {
+					b.WriteString(", órfão")
+				}
+				b.WriteString(")\n")
+				escrever(no.Filhos, nivel+1)
+			}
+		}
+		escrever(h.Arvore, 0)
+		b.WriteString("\n")
+	}
+
+	if t := r.Threads; t != nil {
+		fmt.Fprintf(&b, "## Threads (modelo %s, escalonador de usuário %s)\n\n", t.Modelo, t.EscalonadorUsuario)
+		b.WriteString("| Thread | Entidade do kernel | Início | Término | Tempo de vida | Tempo de espera | Resposta |\n")
+		b.WriteString("|---|---|---:|---:|---:|---:|---:|\n")
+		for _, m := range t.Threads {
+			fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %d | %d |\n", escaparMarkdown(m.Thread), escaparMarkdown(m.Entidade),
+				m.Inicio, m.Termino, m.TempoVida, m.TempoEspera, m.TempoResposta)
+		}
+		b.WriteString("\n| Processo | Threads | Entidades do kernel | Término | Tempo de vida | Tempo de espera |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, p := range t.Processos {
+			fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d |\n", escaparMarkdown(p.Processo), p.Threads, p.Entidades,
+				p.Termino, p.TempoVida, p.TempoEspera)
+		}
+		b.WriteString("\n")
+	}
+
+	if r.Recursos != nil && len(r.Recursos.Inversoes) > 0 {
+		b.WriteString("## Inversões de prioridade\n\n")
+		b.WriteString("| Intervalo | Bloqueado | Recurso | Detentor | Executando |\n|---|---|---|---|---|\n")
+		for _, inv := range r.Recursos.Inversoes {
+			fmt.Fprintf(&b, "| %d-%d | %s | %s | %s | %s |\n", inv.Inicio, inv.Fim, escaparMarkdown(inv.Processo),
+				escaparMarkdown(inv.Recurso), escaparMarkdown(inv.Detentor), escaparMarkdown(inv.Executando))
+		}
+		b.WriteString("\n")
+	}
+
+	if r.Recursos != nil && len(r.Recursos.Deadlocks) > 0 {
+		b.WriteString("## Deadlocks\n\n")
+		b.WriteString("| Instante | Processos | Vítima |\n|---:|---|---|\n")
+		for _, d := range r.Recursos.Deadlocks {
+			vitima := d.Vitima
+			if vitima == "" {
+				vitima = "-"
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s |\n", d.Instante, escaparMarkdown(strings.Join(d.Processos, ", ")), escaparMarkdown(vitima))
+		}
+		b.WriteString("\n")
+	}
+
+	// Sequência de execução da CPU, mais fácil de ler que o diagrama completo
+	linha := linhaDoTempoDoResultado(r)
+	b.WriteString("## Gantt\n\n")
+	b.WriteString("| Intervalo | CPU |\n|---|---|\n")
+	for _, seg := range linha.execucaoNaCPU() {
+		nome := estadoOcioso
+		if seg.Trilha >= 0 {
+			nome = escaparMarkdown(linha.Trilhas[seg.Trilha])
+		}
+		fmt.Fprintf(&b, "| %d-%d | %s |\n", seg.Inicio, seg.Fim, nome)
+	}
+	b.WriteString("\n")
+
+	// Diagrama de tempo no mesmo formato exibido pelo frontend
+	b.WriteString("## Diagrama de tempo\n\n")
+	b.WriteString("`##` executando, `--` esperando")
+	if r.Memoria != nil {
+		b.WriteString(", `" + marcaForaDaMemoria + "` aguardando memória")
+	}
+	if r.Recursos != nil {
+		b.WriteString(", `" + marcaBloqueado + "` bloqueado em recurso")
+	}
+	if r.Sincronizacao != nil {
+		b.WriteString(", `" + marcaSemaforo + "` bloqueado em semáforo, `" + marcaES + "` em E/S")
+	}
+	if r.Hierarquia != nil {
+		b.WriteString(", `" + marcaEsperaFilhos + "` em wait()")
+	}
+	b.WriteString("\n\n")
+	b.WriteString("| Tempo |")
+	for _, nome := range linha.Trilhas {
+		fmt.Fprintf(&b, " %s |", escaparMarkdown(nome))
+	}
+	b.WriteString("\n|---|" + strings.Repeat(":---:|", len(linha.Trilhas)) + "\n")
+	for t, estados := range r.DiagramaTempo {
+		fmt.Fprintf(&b, "| %d-%d |", t, t+1)
+		for _, estado := range estados {
+			if strings.TrimSpace(estado) == "" {
+				b.WriteString("   |")
+				continue
+			}
+			fmt.Fprintf(&b, " `%s` |", estado)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.Bytes(), nil
+}
+
+// escaparMarkdown evita que o nome de um processo quebre a tabela
+func escaparMarkdown(texto string) string {
+	return strings.ReplaceAll(texto, "|", "\\|")
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	r := resultadoDeTeste()
+	r.Algoritmo, r.Quantum = "rr", 2
+	r.OrdemProcessos[1] = "B|C "
+	r.Processos[1].Processo = "B|C"
+	r.Processos[1].Inanicao = true
+	r.Estatisticas = Estatisticas{Processos: 2, Concluidos: 2}
+
+	saida, err := (&Markdown{}).exportar(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	texto := string(saida)
+
+	trechos := []string{
+		"# Escalonamento RR (quantum 2)\n",
+		"| Processos concluídos | 2 de 2 |\n",
+		"| Tempo médio de vida (turnaround) | 3.50 |\n",
+		"| B\\|C (inanição) | 0 |",
+		"## Gantt\n\n| Intervalo | CPU |\n|---|---|\n| 0-1 | A |\n| 1-3 | B\\|C |\n| 3-4 | ocioso |\n| 4-5 | A |\n",
+		"| Tempo | A | B\\|C |\n|---|:---:|:---:|\n| 0-1 | `##` |   |\n| 1-2 | `--` | `##` |\n",
+	}
+	for _, trecho := range trechos {
+		if !strings.Contains(texto, trecho) {
+			t.Errorf("relatório sem o trecho %q:\n%s", trecho, texto)
+		}
+	}
+
+	// As seções opcionais só aparecem quando a simulação tem os dados
+	for _, secao := range []string{"## Vazão em janelas", "## Threads", "## Inversões", "## Deadlocks", "## Árvore de processos"} {
+		if strings.Contains(texto, secao) {
+			t.Errorf("seção %q inesperada", secao)
+		}
+	}
+}
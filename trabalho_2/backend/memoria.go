@@ -0,0 +1,303 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Políticas de admissão do escalonador de longo prazo
+const (
+	admissaoFIFO     = "fifo"     // Admite na ordem de chegada; o primeiro que não cabe bloqueia os demais
+	admissaoFirstFit = "firstFit" // Admite qualquer processo da fila que caiba na memória livre
+)
+
+// Marcação do diagrama para os processos que chegaram mas estão fora da memória
+// (na fila de jobs ou suspensos pelo swap)
+const marcaForaDaMemoria = ".."
+
+// ControleMemoria é o controle de admissão pela memória: o escalonador de longo prazo só coloca
+// um processo na fila de prontos quando a memória que ele pede está livre, e o de médio prazo,
+// com swapping, retira processos prontos da memória para dar lugar a quem espera há muito tempo
+// A memória é tratada como capacidade total, sem alocação contígua (veja POST /memory/allocation)
+type ControleMemoria struct {
+	total      int
+	livre      int
+	admissao   string
+	swapping   bool
+	esperaSwap int         // Espera por memória a partir da qual o escalonador de médio prazo age
+	filaJobs   []*Processo // Processos que chegaram e esperam a primeira admissão
+	suspensos  []*Processo // Processos retirados da memória pelo swap, esperando para voltar
+	emMemoria  []*Processo // Processos admitidos que ainda não terminaram
+	grau       []int       // Grau de multiprogramação em cada instante
+	ocupacao   []int       // Memória ocupada em cada instante
+	swapsOut   int
+	swapsIn    int
+}
+
+// ResultadoMemoria resume o efeito do controle de admissão na simulação
+type ResultadoMemoria struct {
+	Total                      int     `json:"total"`
+	Admissao                   string  `json:"admissao"`
+	Swapping                   bool    `json:"swapping"`
+	EsperaSwap                 int     `json:"esperaSwap,omitempty"`
+	GrauMultiprogramacao       []int   `json:"grauMultiprogramacao"` // Processos na memória em cada instante
+	GrauMultiprogramacaoMedio  float64 `json:"grauMultiprogramacaoMedio"`
+	GrauMultiprogramacaoMaximo int     `json:"grauMultiprogramacaoMaximo"`
+	Ocupacao                   []int   `json:"ocupacao"` // Memória ocupada em cada instante
+	OcupacaoMedia              float64 `json:"ocupacaoMedia"`
+	EsperaMemoriaMedia         float64 `json:"esperaMemoriaMedia"` // Tempo médio fora da memória (fila de jobs e swap)
+	SwapsOut                   int     `json:"swapsOut"`
+	SwapsIn                    int     `json:"swapsIn"`
+}
+
+// novoControleMemoria valida e monta o controle de admissão pedido na requisição
+// Sem memorySize não há controle: todo processo entra na fila de prontos ao chegar
+func novoControleMemoria(body ContextBody) (*ControleMemoria, error) {
+	if body.MemorySize == 0 {
+		if body.Admission != "" || body.Swapping || body.SwapAfter != 0 {
+			return nil, fmt.Errorf("admission, swapping e swapAfter exigem memorySize")
+		}
+		return nil, nil
+	}
+
+	m := &ControleMemoria{
+		total:      body.MemorySize,
+		livre:      body.MemorySize,
+		admissao:   body.Admission,
+		swapping:   body.Swapping,
+		esperaSwap: body.SwapAfter,
+	}
+	if m.admissao == "" {
+		m.admissao = admissaoFIFO
+	}
+	if m.admissao != admissaoFIFO && m.admissao != admissaoFirstFit {
+		return nil, fmt.Errorf("admission inválido: use %q ou %q", admissaoFIFO, admissaoFirstFit)
+	}
+	if m.swapping && m.esperaSwap == 0 {
+		m.esperaSwap = body.Quantum
+	}
+	return m, nil
+}
+
+// validarMemoria confere os campos de memória da requisição
+func validarMemoria(body ContextBody) error {
+	if body.MemorySize < 0 {
+		return fmt.Errorf("memorySize não pode ser negativo")
+	}
+	if body.SwapAfter < 0 {
+		return fmt.Errorf("swapAfter não pode ser negativo")
+	}
+	for i, p := range body.Input {
+		if body.MemorySize > 0 && p.Memory > body.MemorySize {
+			return fmt.Errorf("o processo %d pede %d de memória, mais que memorySize (%d)", i+1, p.Memory, body.MemorySize)
+		}
+	}
+	return nil
+}
+
+// chegouAgora indica se o processo entra na fila de prontos neste instante
+// Sem controle de memória é o instante de chegada; com ele, o instante da primeira admissão
+func (s *Simulador) chegouAgora(p *Processo) bool {
+	if s.memoria == nil {
+		return p.instanteCriacao == s.tempoAtual
+	}
+	return p.instanteAdmissao == s.tempoAtual
+}
+
+// foraDaMemoria indica se o processo chegou mas ainda não está (ou deixou de estar) na memória
+func (s *Simulador) foraDaMemoria(p *Processo) bool {
+	return s.memoria != nil && p.instanteCriacao <= s.tempoAtual && p.tempoRestante > 0 && !p.naMemoria
+}
+
+// escalonarMemoria roda os escalonadores de longo e médio prazo no instante atual
+// Deve ser chamada com o relógio já avançado; processoAtual é quem ocupou a CPU no instante que terminou
+func (s *Simulador) escalonarMemoria(processoAtual *Processo) {
+	m := s.memoria
+
+	// Estado da memória durante o instante que terminou
+	if s.tempoAtual > 0 {
+		m.grau = append(m.grau, len(m.emMemoria))
+		m.ocupacao = append(m.ocupacao, m.total-m.livre)
+		for _, p := range m.filaJobs {
+			p.esperaMemoria++
+		}
+		for _, p := range m.suspensos {
+			p.esperaMemoria++
+		}
+	}
+
+	// Quem terminou devolve a memória
+	if processoAtual != nil && processoAtual.tempoRestante == 0 {
+		s.liberarMemoria(processoAtual)
+	}
+
+	for _, p := range s.processos {
+		if p.instanteCriacao == s.tempoAtual {
+			p.esperaMemoriaDesde = s.tempoAtual
+			m.filaJobs = append(m.filaJobs, p)
+		}
+	}
+
+	s.admitirEsperando()
+
+	if m.swapping {
+		s.swapOut()
+	}
+}
+
+// admitirEsperando coloca na memória os processos que esperam por ela e cabem no espaço livre
+// Quem foi suspenso já executou parte do trabalho e volta antes dos novos
+// Na política FIFO, os novos só entram depois que todos os suspensos voltaram
+func (s *Simulador) admitirEsperando() {
+	m := s.memoria
+	m.suspensos = s.admitir(m.suspensos, s.retornarDoSwap)
+	if m.admissao != admissaoFIFO || len(m.suspensos) == 0 {
+		m.filaJobs = s.admitir(m.filaJobs, s.admitirNovo)
+	}
+}
+
+// admitir percorre a fila (de jobs ou de suspensos) colocando na memória quem couber, conforme a política
+// Devolve os processos que continuam esperando
+func (s *Simulador) admitir(fila []*Processo, entrar func(*Processo)) []*Processo {
+	m := s.memoria
+	restantes := fila[:0]
+	bloqueada := false
+	for _, p := range fila {
+		if bloqueada || p.memoria > m.livre {
+			restantes = append(restantes, p)
+			bloqueada = m.admissao == admissaoFIFO
+			continue
+		}
+		s.colocarNaMemoria(p)
+		entrar(p)
+	}
+	return restantes
+}
+
+// colocarNaMemoria reserva a memória do processo
+func (s *Simulador) colocarNaMemoria(p *Processo) {
+	m := s.memoria
+	m.livre -= p.memoria
+	m.emMemoria = append(m.emMemoria, p)
+	p.naMemoria = true
+}
+
+// admitirNovo marca a primeira admissão; o algoritmo coloca o processo na fila de prontos em adicionarProcessosNovos
+func (s *Simulador) admitirNovo(p *Processo) {
+	p.instanteAdmissao = s.tempoAtual
+	s.registrarMemoria(p, "admissao")
+}
+
+// retornarDoSwap devolve o processo suspenso ao fim da fila de prontos
+// Os algoritmos que ordenam a fila o reposicionam logo em seguida, em adicionarProcessosNovos
+func (s *Simulador) retornarDoSwap(p *Processo) {
+	s.memoria.swapsIn++
+	s.filaDeExecucao = append(s.filaDeExecucao, p)
+	s.registrarMemoria(p, "retorno")
+}
+
+// liberarMemoria retira o processo da memória
+func (s *Simulador) liberarMemoria(p *Processo) {
+	m := s.memoria
+	m.livre += p.memoria
+	p.naMemoria = false
+	m.emMemoria = slices.DeleteFunc(m.emMemoria, func(q *Processo) bool { return q == p })
+}
+
+// swapOut é o escalonador de médio prazo: se o primeiro processo que espera por memória
+// (suspenso ou na fila de jobs) já esperou esperaSwap unidades e não cabe, retira da memória
+// processos prontos, a partir do fim da fila de prontos, até liberar o espaço necessário
+// O processo que está na CPU nunca é suspenso, e nada é suspenso se não resolver a espera
+func (s *Simulador) swapOut() {
+	m := s.memoria
+
+	var esperando *Processo
+	if len(m.suspensos) > 0 {
+		esperando = m.suspensos[0]
+	} else if len(m.filaJobs) > 0 {
+		esperando = m.filaJobs[0]
+	}
+	if esperando == nil || esperando.memoria <= m.livre || s.tempoAtual-esperando.esperaMemoriaDesde < m.esperaSwap {
+		return
+	}
+
+	// Escolhe as vítimas do fim para o começo da fila de prontos
+	liberado := m.livre
+	var vitimas []*Processo
+	for i := len(s.filaDeExecucao) - 1; i >= 0 && liberado < esperando.memoria; i-- {
+		if p := s.filaDeExecucao[i]; p.memoria > 0 {
+			vitimas = append(vitimas, p)
+			liberado += p.memoria
+		}
+	}
+	if liberado < esperando.memoria {
+		return
+	}
+
+	for _, p := range vitimas {
+		s.filaDeExecucao = slices.DeleteFunc(s.filaDeExecucao, func(q *Processo) bool { return q == p })
+		s.liberarMemoria(p)
+		p.swapsOut++
+		p.esperaMemoriaDesde = s.tempoAtual
+		m.swapsOut++
+		m.suspensos = append(m.suspensos, p)
+		s.registrarMemoria(p, "suspensao")
+	}
+
+	// O espaço liberado vai só para quem esperava, para que as vítimas não voltem no mesmo instante
+	s.colocarNaMemoria(esperando)
+	if esperando == m.suspensos[0] {
+		m.suspensos = m.suspensos[1:]
+		s.retornarDoSwap(esperando)
+	} else {
+		m.filaJobs = m.filaJobs[1:]
+		s.admitirNovo(esperando)
+	}
+}
+
+// registrarMemoria anota no rastro as decisões dos escalonadores de longo e médio prazo
+func (s *Simulador) registrarMemoria(p *Processo, tipo string) {
+	if !s.rastrear {
+		return
+	}
+	s.rastro = append(s.rastro, DecisaoEscalonamento{
+		Instante:  s.tempoAtual,
+		Tipo:      tipo,
+		Escolhido: p.rotulo(),
+		Motivo:    fmt.Sprintf("memória livre: %d de %d", s.memoria.livre, s.memoria.total),
+	})
+}
+
+// resultado resume o controle de memória ao fim da simulação
+func (m *ControleMemoria) resultado(processos []*Processo) *ResultadoMemoria {
+	r := &ResultadoMemoria{
+		Total:                m.total,
+		Admissao:             m.admissao,
+		Swapping:             m.swapping,
+		EsperaSwap:           m.esperaSwap,
+		GrauMultiprogramacao: m.grau,
+		Ocupacao:             m.ocupacao,
+		SwapsOut:             m.swapsOut,
+		SwapsIn:              m.swapsIn,
+	}
+
+	if n := len(m.grau); n > 0 {
+		somaGrau, somaOcupacao := 0, 0
+		for i := range n {
+			somaGrau += m.grau[i]
+			somaOcupacao += m.ocupacao[i]
+			r.GrauMultiprogramacaoMaximo = max(r.GrauMultiprogramacaoMaximo, m.grau[i])
+		}
+		r.GrauMultiprogramacaoMedio = float64(somaGrau) / float64(n)
+		r.OcupacaoMedia = float64(somaOcupacao) / float64(n)
+	}
+
+	if len(processos) > 0 {
+		soma := 0
+		for _, p := range processos {
+			soma += p.esperaMemoria
+		}
+		r.EsperaMemoriaMedia = float64(soma) / float64(len(processos))
+	}
+	return r
+}
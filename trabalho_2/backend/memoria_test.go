@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// admissoes devolve o término, o instante da primeira admissão e a espera por memória de cada processo
+func admissoes(r Resultado) map[string][3]int {
+	m := map[string][3]int{}
+	for _, p := range r.Processos {
+		admissao := -1
+		if p.Admissao != nil {
+			admissao = *p.Admissao
+		}
+		m[p.Nome] = [3]int{p.Termino, admissao, p.EsperaMemoria}
+	}
+	return m
+}
+
+func TestAdmissaoPorMemoria(t *testing.T) {
+	// A e B não cabem juntos em 10 unidades; C cabe ao lado de A
+	entrada := []Processes{
+		{Begin: 0, Duration: 3, Priority: 1, Name: "A", Memory: 6},
+		{Begin: 0, Duration: 2, Priority: 1, Name: "B", Memory: 6},
+		{Begin: 0, Duration: 1, Priority: 1, Name: "C", Memory: 3},
+	}
+	casos := []struct {
+		admissao      string
+		esperado      map[string][3]int
+		ocupacao      []int
+		esperaEmMedia float64
+	}{
+		// FIFO: C espera atrás de B, e os dois entram quando A termina em t=3
+		{admissaoFIFO, map[string][3]int{"A": {3, 0, 0}, "B": {5, 3, 3}, "C": {6, 3, 3}}, []int{6, 6, 6, 9, 9, 3}, 2},
+		// First fit: C passa na frente de B e entra em t=0
+		{"firstFit", map[string][3]int{"A": {3, 0, 0}, "B": {6, 3, 3}, "C": {4, 0, 0}}, []int{9, 9, 9, 9, 6, 6}, 1},
+	}
+
+	for _, c := range casos {
+		t.Run(c.admissao, func(t *testing.T) {
+			body := ContextBody{Alg: "fcfs", Quantum: 1, MemorySize: 10, Admission: c.admissao, TieBreak: []string{}, Input: entrada}
+			r, err := processScheduler(context.Background(), body, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obtido := admissoes(r); !reflect.DeepEqual(obtido, c.esperado) {
+				t.Errorf("término, admissão e espera %v, esperado %v", obtido, c.esperado)
+			}
+			if !reflect.DeepEqual(r.Memoria.Ocupacao, c.ocupacao) || r.Memoria.EsperaMemoriaMedia != c.esperaEmMedia {
+				t.Errorf("ocupação %v e espera média %v, esperado %v e %v", r.Memoria.Ocupacao, r.Memoria.EsperaMemoriaMedia, c.ocupacao, c.esperaEmMedia)
+			}
+		})
+	}
+}
+
+func TestSwapping(t *testing.T) {
+	// Em t=2, C esperou swapAfter unidades e A, pronto na fila, é suspenso para dar lugar a ele
+	body := ContextBody{Alg: "rr", Quantum: 1, MemorySize: 10, Swapping: true, SwapAfter: 2, Input: []Processes{
+		{Begin: 0, Duration: 4, Priority: 1, Name: "A", Memory: 6},
+		{Begin: 0, Duration: 4, Priority: 1, Name: "B", Memory: 3},
+		{Begin: 0, Duration: 2, Priority: 1, Name: "C", Memory: 6},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m := r.Memoria; m.SwapsOut != 3 || m.SwapsIn != 3 || m.GrauMultiprogramacaoMaximo != 2 {
+		t.Errorf("swaps %d/%d, grau máximo %d; esperado 3/3 e 2", m.SwapsOut, m.SwapsIn, m.GrauMultiprogramacaoMaximo)
+	}
+	swaps := map[string]int{}
+	for _, p := range r.Processos {
+		swaps[p.Nome] = p.Swaps
+	}
+	if esperado := map[string]int{"A": 2, "B": 0, "C": 1}; !reflect.DeepEqual(swaps, esperado) {
+		t.Errorf("swaps por processo %v, esperado %v", swaps, esperado)
+	}
+	if terminos := termino(r); !reflect.DeepEqual(terminos, map[string]int{"A": 10, "B": 8, "C": 7}) {
+		t.Errorf("términos %v", terminos)
+	}
+}
+
+func TestAbortoDevolveMemoria(t *testing.T) {
+	// A e B ocupam 4 cada e entram em deadlock em t=3; B é abortado
+	// C só cabe quando a memória de B volta ao sistema
+	casos := []struct {
+		nome     string
+		memoria  int
+		esperado map[string][3]int
+		ocupacao []int
+	}{
+		// Com 6, C cabe assim que B é abortado em t=3
+		{"cabe após o aborto", 6, map[string][3]int{"A": {6, 0, 0}, "B": {-1, 0, 0}, "C": {7, 3, 2}}, []int{8, 8, 8, 10, 10, 10, 6}},
+		// Com 8, C precisa também da memória de A, que termina em t=5
+		{"cabe após o fim de A", 8, map[string][3]int{"A": {5, 0, 0}, "B": {-1, 0, 0}, "C": {7, 5, 4}}, []int{8, 8, 8, 4, 4, 8, 8}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			body := ContextBody{Alg: "rr", Quantum: 1, MemorySize: 10, DeadlockRecovery: recuperacaoAbortar, Input: []Processes{
+				{Begin: 0, Duration: 3, Priority: 2, Name: "A", Memory: 4, CriticalSections: []CriticalSection{
+					{Resource: "r1", Start: 0, Duration: 3}, {Resource: "r2", Start: 1, Duration: 2}}},
+				{Begin: 0, Duration: 3, Priority: 1, Name: "B", Memory: 4, CriticalSections: []CriticalSection{
+					{Resource: "r2", Start: 0, Duration: 3}, {Resource: "r1", Start: 1, Duration: 2}}},
+				{Begin: 1, Duration: 2, Priority: 1, Name: "C", Memory: c.memoria},
+			}}
+			r, err := processScheduler(context.Background(), body, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if r.Interrompido || !r.Processos[1].Abortado {
+				t.Fatalf("interrompido %v, B abortado %v", r.Interrompido, r.Processos[1].Abortado)
+			}
+			if obtido := admissoes(r); !reflect.DeepEqual(obtido, c.esperado) {
+				t.Errorf("término, admissão e espera %v, esperado %v", obtido, c.esperado)
+			}
+			if !reflect.DeepEqual(r.Memoria.Ocupacao, c.ocupacao) {
+				t.Errorf("ocupação %v, esperado %v", r.Memoria.Ocupacao, c.ocupacao)
+			}
+		})
+	}
+}
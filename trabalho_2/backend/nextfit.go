@@ -0,0 +1,33 @@
+package main
+
+// NextFit funciona como o first fit, mas começa a busca de onde a última alocação parou,
+// dando no máximo uma volta pela memória
+type NextFit struct {
+	s       *SimuladorAlocacao
+	posicao int // Endereço logo após o último bloco alocado
+}
+
+func (alg *NextFit) alocar(tamanho int) int {
+	segmentos := alg.s.segmentos
+
+	// SegmentoMemoria que contém a posição onde a última busca parou
+	inicio := 0
+	for i, seg := range segmentos {
+		if seg.inicio <= alg.posicao && alg.posicao < seg.inicio+seg.tamanho {
+			inicio = i
+		}
+	}
+
+	for k := range len(segmentos) {
+		i := (inicio + k) % len(segmentos)
+		if segmentos[i].livre() && segmentos[i].tamanho >= tamanho {
+			alg.posicao = (segmentos[i].inicio + tamanho) % alg.s.tamanho
+			return alg.s.dividir(i, tamanho)
+		}
+	}
+	return -1
+}
+
+func (alg *NextFit) liberar(i int) {
+	alg.s.juntarVizinhos(i)
+}
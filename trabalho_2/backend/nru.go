@@ -0,0 +1,50 @@
+package main
+
+// NRU substitui uma página da menor classe, formada pelos bits R (referenciada) e M (modificada):
+// classe 0 = R0 M0, 1 = R0 M1, 2 = R1 M0, 3 = R1 M1
+// Os bits R são zerados periodicamente, simulando a interrupção de relógio
+type NRU struct {
+	s         *SimuladorPaginacao
+	bitR      []bool
+	bitM      []bool
+	intervalo int // A cada quantas referências os bits R são zerados
+}
+
+// classe devolve a classe NRU da página no quadro
+func (alg *NRU) classe(quadro int) int {
+	c := 0
+	if alg.bitR[quadro] {
+		c += 2
+	}
+	if alg.bitM[quadro] {
+		c++
+	}
+	return c
+}
+
+func (alg *NRU) escolherVitima() int {
+	// Empates ficam com o menor quadro, para a simulação ser reproduzível
+	vitima := 0
+	for i := range alg.bitR {
+		if alg.classe(i) < alg.classe(vitima) {
+			vitima = i
+		}
+	}
+	return vitima
+}
+
+func (alg *NRU) aoAcessar(quadro int, falta bool) {
+	if falta {
+		alg.bitM[quadro] = false
+	}
+	alg.bitR[quadro] = true
+	if alg.s.escrita(alg.s.instante) {
+		alg.bitM[quadro] = true
+	}
+
+	if (alg.s.instante+1)%alg.intervalo == 0 {
+		for i := range alg.bitR {
+			alg.bitR[i] = false
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+// OPT (ótimo de Belady) substitui a página que vai demorar mais para ser usada de novo
+// Precisa conhecer as referências futuras, então serve de referência para comparar os outros algoritmos
+type OPT struct {
+	s *SimuladorPaginacao
+}
+
+func (alg *OPT) escolherVitima() int {
+	vitima, maisDistante := 0, -1
+	for i, pagina := range alg.s.quadros {
+		proximo := alg.proximoUso(pagina)
+		// Uma página que não será mais usada é a melhor vítima; empates ficam com o menor quadro
+		if proximo > maisDistante {
+			vitima, maisDistante = i, proximo
+		}
+	}
+	return vitima
+}
+
+// proximoUso devolve o índice da próxima referência à página, ou len(referencias) se não houver
+func (alg *OPT) proximoUso(pagina int) int {
+	for i := alg.s.instante + 1; i < len(alg.s.referencias); i++ {
+		if alg.s.referencias[i] == pagina {
+			return i
+		}
+	}
+	return len(alg.s.referencias)
+}
+
+func (alg *OPT) aoAcessar(quadro int, falta bool) {}
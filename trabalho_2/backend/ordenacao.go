@@ -0,0 +1,163 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// criterio descreve uma chave de ordenação usada por um escalonador
+// O processo com o menor valor de chave é o mais prioritário, exceto nos critérios
+// de prioridade, cujo sentido depende da convenção escolhida na requisição
+type criterio struct {
+	nome       string                // Nome do critério, usado na explicação
+	chave      func(p *Processo) int // Valor do processo para este critério
+	prioridade bool                  // A chave é uma prioridade (o sentido segue a convenção)
+}
+
+var (
+	criterioTempoRestante      = criterio{"tempoRestante", func(p *Processo) int { return p.tempoRestante }, false}
+	criterioChegada            = criterio{"instanteCriacao", func(p *Processo) int { return p.instanteCriacao }, false}
+	criterioId                 = criterio{"id", func(p *Processo) int { return p.id }, false}
+	criterioSorteio            = criterio{"sorteio", func(p *Processo) int { return p.sorteio }, false}
+	criterioPrioridadeOriginal = criterio{"prioridadeOriginal", func(p *Processo) int { return p.prioridadeOriginal }, true}
+	criterioPrioridadeAtual    = criterio{"prioridadeAtual", func(p *Processo) int { return p.prioridadeAtual }, true}
+	criterioTempoEnvelhecido   = criterio{"tempoRestanteEnvelhecido", func(p *Processo) int { return p.tempoRestante - p.bonusEnvelhecimento }, false}
+)
+
+// Critérios de desempate aceitos no campo tieBreak da requisição
+var criteriosDesempate = map[string]criterio{
+	"arrival":   criterioChegada,
+	"id":        criterioId,
+	"remaining": criterioTempoRestante,
+	"random":    criterioSorteio,
+}
+
+// desempatePadrao é a cadeia de desempate que cada algoritmo usa quando a requisição não informa
+// uma, reproduzindo o comportamento original (em todos os casos o último recurso é a ordem na fila)
+var desempatePadrao = map[string][]string{
+	"fcfs": {"remaining"},
+	"sjf":  {"arrival", "id"},
+	"srtf": {"arrival", "id"},
+	"psp":  {"remaining"},
+	"pcpp": {"remaining"},
+	"rr":   {},
+	"rrpe": {},
+}
+
+// Convenções de prioridade aceitas no campo priorityOrder
+const (
+	prioridadeMaior = "higher" // Maior número = maior prioridade (padrão)
+	prioridadeMenor = "lower"  // Menor número = maior prioridade
+)
+
+// RegrasOrdenacao são as regras de prioridade e desempate usadas pelo simulador,
+// devolvidas no resultado para deixar claro como os empates foram resolvidos
+type RegrasOrdenacao struct {
+	ConvencaoPrioridade string   `json:"convencaoPrioridade"`
+	Desempate           []string `json:"desempate"`         // Cadeia aplicada após o critério do algoritmo
+	Semente             *int64   `json:"semente,omitempty"` // Semente do desempate "random"
+
+	maiorPrimeiro bool       // Maior número = maior prioridade
+	criterios     []criterio // Cadeia de desempate já convertida em critérios
+}
+
+// novasRegras valida e monta as regras de ordenação pedidas na requisição
+func novasRegras(body ContextBody) (RegrasOrdenacao, error) {
+	regras := RegrasOrdenacao{ConvencaoPrioridade: prioridadeMaior, maiorPrimeiro: true}
+
+	switch body.PriorityOrder {
+	case "", prioridadeMaior:
+	case prioridadeMenor:
+		regras.ConvencaoPrioridade = prioridadeMenor
+		regras.maiorPrimeiro = false
+	default:
+		return regras, fmt.Errorf("priorityOrder inválido: use %q ou %q", prioridadeMaior, prioridadeMenor)
+	}
+
+	nomes := body.TieBreak
+	if nomes == nil {
+		nomes = desempatePadrao[body.Alg]
+	}
+
+	repetidos := map[string]bool{}
+	for _, nome := range nomes {
+		c, ok := criteriosDesempate[nome]
+		if !ok {
+			return regras, fmt.Errorf("critério de desempate inválido: %s", nome)
+		}
+		if repetidos[nome] {
+			return regras, fmt.Errorf("critério de desempate repetido: %s", nome)
+		}
+		repetidos[nome] = true
+
+		regras.criterios = append(regras.criterios, c)
+		regras.Desempate = append(regras.Desempate, nome)
+
+		// Sem semente informada, sorteia uma e devolve no resultado para permitir repetir a simulação
+		if nome == "random" {
+			semente := time.Now().UnixNano()
+			if body.Seed != nil {
+				semente = *body.Seed
+			}
+			regras.Semente = &semente
+		}
+	}
+	regras.Desempate = append(regras.Desempate, "fila")
+
+	return regras, nil
+}
+
+// sortearDesempate atribui a cada processo o valor usado pelo critério "random"
+func (r RegrasOrdenacao) sortearDesempate(processos []*Processo) {
+	if r.Semente == nil {
+		return
+	}
+
+	gerador := rand.New(rand.NewSource(*r.Semente))
+	for _, p := range processos {
+		p.sorteio = gerador.Int()
+	}
+}
+
+// comparar devolve um número negativo se a vem antes de b segundo o critério
+func (s *Simulador) comparar(c criterio, a, b *Processo) int {
+	if c.prioridade && s.regras.maiorPrimeiro {
+		return cmp.Compare(c.chave(b), c.chave(a))
+	}
+	return cmp.Compare(c.chave(a), c.chave(b))
+}
+
+// cadeia junta os critérios do algoritmo com a cadeia de desempate configurada
+func (s *Simulador) cadeia(principais []criterio) []criterio {
+	return append(append([]criterio{}, principais...), s.regras.criterios...)
+}
+
+// ordenar ordena os processos pelos critérios do algoritmo e, em caso de empate,
+// pela cadeia de desempate; empates restantes mantêm a ordem na fila
+func (s *Simulador) ordenar(processos []*Processo, principais []criterio) {
+	criterios := s.cadeia(principais)
+	sort.SliceStable(processos, func(i, j int) bool {
+		for _, c := range criterios {
+			if r := s.comparar(c, processos[i], processos[j]); r != 0 {
+				return r < 0
+			}
+		}
+		return false
+	})
+}
+
+// ordenarFila ordena a fila de execução pelos critérios do algoritmo e pela cadeia de desempate
+func (s *Simulador) ordenarFila(principais []criterio) {
+	s.ordenar(s.filaDeExecucao, principais)
+}
+
+// prioridadeMaiorQue indica se a prioridade a é estritamente mais importante que b na convenção escolhida
+func (s *Simulador) prioridadeMaiorQue(a, b int) bool {
+	if s.regras.maiorPrimeiro {
+		return a > b
+	}
+	return a < b
+}
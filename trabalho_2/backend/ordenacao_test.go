@@ -0,0 +1,97 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrdenar(t *testing.T) {
+	casos := []struct {
+		convencao string
+		desempate []string
+		esperado  []string
+	}{
+		// Sem desempate, os empates mantêm a ordem da fila
+		{prioridadeMaior, []string{}, []string{"P2", "P4", "P3", "P1"}},
+		{prioridadeMaior, []string{"arrival"}, []string{"P4", "P2", "P1", "P3"}},
+		{prioridadeMaior, []string{"remaining", "id"}, []string{"P4", "P2", "P3", "P1"}},
+		{prioridadeMenor, []string{"remaining"}, []string{"P3", "P1", "P4", "P2"}},
+		{prioridadeMenor, []string{"id"}, []string{"P1", "P3", "P2", "P4"}},
+	}
+
+	for _, c := range casos {
+		regras, err := novasRegras(ContextBody{Alg: "psp", PriorityOrder: c.convencao, TieBreak: c.desempate})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fila := []*Processo{
+			{id: 3, instanteCriacao: 2, tempoRestante: 3, prioridadeAtual: 2},
+			{id: 2, instanteCriacao: 1, tempoRestante: 3, prioridadeAtual: 4},
+			{id: 1, instanteCriacao: 0, tempoRestante: 5, prioridadeAtual: 2},
+			{id: 4, instanteCriacao: 0, tempoRestante: 1, prioridadeAtual: 4},
+		}
+		s := novoSimulador(fila, 1)
+		s.regras = regras
+		s.ordenar(fila, []criterio{criterioPrioridadeAtual})
+
+		ordem := make([]string, len(fila))
+		for i, p := range fila {
+			ordem[i] = p.rotulo()
+		}
+		if !slices.Equal(ordem, c.esperado) {
+			t.Errorf("%s %v: ordem %v, esperado %v", c.convencao, c.desempate, ordem, c.esperado)
+		}
+	}
+}
+
+func TestNovasRegras(t *testing.T) {
+	semente := int64(42)
+	casos := []struct {
+		body      ContextBody
+		desempate []string
+		erro      bool
+	}{
+		// Sem tieBreak, cada algoritmo usa sua cadeia padrão
+		{ContextBody{Alg: "sjf"}, []string{"arrival", "id", "fila"}, false},
+		{ContextBody{Alg: "rr"}, []string{"fila"}, false},
+		{ContextBody{Alg: "sjf", TieBreak: []string{}}, []string{"fila"}, false},
+		{ContextBody{Alg: "fcfs", TieBreak: []string{"random"}, Seed: &semente}, []string{"random", "fila"}, false},
+		{ContextBody{Alg: "fcfs", TieBreak: []string{"idade"}}, nil, true},
+		{ContextBody{Alg: "fcfs", TieBreak: []string{"id", "id"}}, nil, true},
+		{ContextBody{Alg: "fcfs", PriorityOrder: "asc"}, nil, true},
+	}
+
+	for _, c := range casos {
+		regras, err := novasRegras(c.body)
+		if (err != nil) != c.erro {
+			t.Errorf("%+v: erro %v", c.body, err)
+			continue
+		}
+		if !c.erro && !slices.Equal(regras.Desempate, c.desempate) {
+			t.Errorf("%+v: desempate %v, esperado %v", c.body, regras.Desempate, c.desempate)
+		}
+	}
+}
+
+func TestSorteioComSemente(t *testing.T) {
+	// A mesma semente sorteia os mesmos valores, para que a simulação possa ser repetida
+	sortear := func() []int {
+		semente := int64(7)
+		regras, _ := novasRegras(ContextBody{TieBreak: []string{"random"}, Seed: &semente})
+		processos := []*Processo{{id: 1}, {id: 2}, {id: 3}}
+		regras.sortearDesempate(processos)
+		valores := make([]int, len(processos))
+		for i, p := range processos {
+			valores[i] = p.sorteio
+		}
+		return valores
+	}
+
+	a, b := sortear(), sortear()
+	if !slices.Equal(a, b) {
+		t.Errorf("sorteios diferentes com a mesma semente: %v e %v", a, b)
+	}
+	if a[0] == a[1] && a[1] == a[2] {
+		t.Errorf("sorteio não variou: %v", a)
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// PagingBody é o corpo de POST /memory/paging
+type PagingBody struct {
+	Alg          string `json:"alg"`          // fifo, lru, opt, clock, nru ou lfu
+	Frames       int    `json:"frames"`       // Quantidade de quadros da memória
+	References   []int  `json:"references"`   // Cadeia de referências (números das páginas)
+	Writes       []bool `json:"writes"`       // Opcional: indica quais referências são escritas (usado pelo NRU)
+	NruReset     int    `json:"nruReset"`     // A cada quantas referências o NRU zera os bits R (padrão 4)
+	Belady       bool   `json:"belady"`       // Faz a verificação da anomalia de Belady (implícito com beladyFrames)
+	BeladyFrames []int  `json:"beladyFrames"` // Quantidades de quadros testadas na verificação da anomalia de Belady
+	TimeoutMs    int    `json:"timeoutMs"`    // Tempo de relógio máximo da simulação; não pode passar do limite do servidor
+}
+
+// Substituidor escolhe qual página sai da memória quando ocorre uma falta com todos os quadros ocupados
+type Substituidor interface {
+	escolherVitima() int              // Quadro cuja página será substituída
+	aoAcessar(quadro int, falta bool) // Atualiza o estado do algoritmo após cada referência
+}
+
+// SimuladorPaginacao gerencia os quadros da memória enquanto percorre a cadeia de referências
+type SimuladorPaginacao struct {
+	quadros     []int      // Página em cada quadro (-1 = livre)
+	referencias []int      // Cadeia de referências
+	escritas    []bool     // Referência i é uma escrita
+	instante    int        // Índice da referência sendo atendida
+	faltas      int        // Contador de faltas de página
+	diagrama    [][]string // Conteúdo dos quadros após cada referência
+	passos      []PassoPaginacao
+	ctx         context.Context // Cancelado quando o cliente desconecta ou o tempo limite acaba
+}
+
+// PassoPaginacao descreve o atendimento de uma referência
+type PassoPaginacao struct {
+	Instante int  `json:"instante"`
+	Pagina   int  `json:"pagina"`
+	Escrita  bool `json:"escrita,omitempty"`
+	Falta    bool `json:"falta"`
+	Quadro   int  `json:"quadro"`           // Quadro onde a página está após a referência
+	Vitima   *int `json:"vitima,omitempty"` // Página que saiu da memória, se houve substituição
+}
+
+// ResultadoPaginacao reúne as métricas e o diagrama da simulação de paginação
+// DiagramaQuadros segue a convenção de diagramaTempo: uma linha por instante e uma coluna por quadro
+type ResultadoPaginacao struct {
+	Algoritmo       string           `json:"algoritmo"`
+	Quadros         int              `json:"quadros"`
+	Referencias     int              `json:"referencias"`
+	Faltas          int              `json:"faltas"`
+	Acertos         int              `json:"acertos"`
+	TaxaFaltas      float64          `json:"taxaFaltas"`
+	DiagramaQuadros [][]string       `json:"diagramaQuadros"`
+	Passos          []PassoPaginacao `json:"passos"`
+	Belady          *AnaliseBelady   `json:"belady,omitempty"`
+}
+
+// FaltasPorQuadros é o total de faltas do algoritmo com uma quantidade de quadros
+type FaltasPorQuadros struct {
+	Quadros int `json:"quadros"`
+	Faltas  int `json:"faltas"`
+}
+
+// AnaliseBelady procura quantidades de quadros em que mais memória causou mais faltas
+type AnaliseBelady struct {
+	Faltas    []FaltasPorQuadros `json:"faltas"`
+	Anomalias []AnomaliaBelady   `json:"anomalias"`
+	Ocorre    bool               `json:"ocorre"`
+}
+
+// AnomaliaBelady registra um aumento de faltas ao passar de Quadros para Quadros+1
+type AnomaliaBelady struct {
+	Quadros      int `json:"quadros"`
+	FaltasAntes  int `json:"faltasAntes"`
+	FaltasDepois int `json:"faltasDepois"`
+}
+
+// Limites da simulação de paginação
+const (
+	maxQuadros       = 1024
+	nruResetPadrao   = 4
+	maxQuadrosBelady = 64
+)
+
+// Dependendo do tipo escolhido, cria o algoritmo de substituição correspondente
+func novoSubstituidor(tipo string, s *SimuladorPaginacao, nruReset int) (Substituidor, error) {
+	n := len(s.quadros)
+	switch tipo {
+	case "fifo":
+		return &FIFO{s, make([]int, n)}, nil
+	case "lru":
+		return &LRU{s, make([]int, n)}, nil
+	case "opt":
+		return &OPT{s}, nil
+	case "clock":
+		return &Clock{s, make([]bool, n), 0}, nil
+	case "nru":
+		return &NRU{s, make([]bool, n), make([]bool, n), nruReset}, nil
+	case "lfu":
+		return &LFU{s, make([]int, n), make([]int, n)}, nil
+	default:
+		return nil, fmt.Errorf("algoritmo de substituição inválido")
+	}
+}
+
+func novoSimuladorPaginacao(quadros int, referencias []int, escritas []bool) *SimuladorPaginacao {
+	s := &SimuladorPaginacao{
+		quadros:     make([]int, quadros),
+		referencias: referencias,
+		escritas:    escritas,
+	}
+	for i := range s.quadros {
+		s.quadros[i] = -1
+	}
+	return s
+}
+
+// quadroDaPagina devolve o quadro que contém a página, ou -1 se ela não está na memória
+func (s *SimuladorPaginacao) quadroDaPagina(pagina int) int {
+	for i, p := range s.quadros {
+		if p == pagina {
+			return i
+		}
+	}
+	return -1
+}
+
+// quadroLivre devolve o primeiro quadro vazio, ou -1 se a memória está cheia
+func (s *SimuladorPaginacao) quadroLivre() int {
+	return s.quadroDaPagina(-1)
+}
+
+// executar atende todas as referências, registrando o diagrama e os passos
+// Para no meio da cadeia se o contexto for cancelado
+func (s *SimuladorPaginacao) executar(alg Substituidor) {
+	for s.instante = 0; s.instante < len(s.referencias); s.instante++ {
+		if s.ctx != nil && s.ctx.Err() != nil {
+			return
+		}
+
+		pagina := s.referencias[s.instante]
+		passo := PassoPaginacao{Instante: s.instante, Pagina: pagina, Escrita: s.escrita(s.instante)}
+
+		quadro := s.quadroDaPagina(pagina)
+		if quadro == -1 {
+			passo.Falta = true
+			s.faltas++
+
+			// Usa um quadro livre; sem nenhum, o algoritmo escolhe a vítima
+			if quadro = s.quadroLivre(); quadro == -1 {
+				quadro = alg.escolherVitima()
+				vitima := s.quadros[quadro]
+				passo.Vitima = &vitima
+			}
+			s.quadros[quadro] = pagina
+		}
+
+		passo.Quadro = quadro
+		alg.aoAcessar(quadro, passo.Falta)
+		s.passos = append(s.passos, passo)
+		s.registrarDiagrama()
+	}
+}
+
+// escrita indica se a referência i é uma escrita
+func (s *SimuladorPaginacao) escrita(i int) bool {
+	return i < len(s.escritas) && s.escritas[i]
+}
+
+// registrarDiagrama registra o conteúdo dos quadros neste instante
+func (s *SimuladorPaginacao) registrarDiagrama() {
+	linha := make([]string, len(s.quadros))
+	for i, p := range s.quadros {
+		if p >= 0 {
+			linha[i] = strconv.Itoa(p)
+		}
+	}
+	s.diagrama = append(s.diagrama, linha)
+}
+
+// resultado monta o resultado da simulação
+func (s *SimuladorPaginacao) resultado(algoritmo string) ResultadoPaginacao {
+	r := ResultadoPaginacao{
+		Algoritmo:       algoritmo,
+		Quadros:         len(s.quadros),
+		Referencias:     len(s.referencias),
+		Faltas:          s.faltas,
+		Acertos:         len(s.referencias) - s.faltas,
+		DiagramaQuadros: s.diagrama,
+		Passos:          s.passos,
+	}
+	if r.Referencias > 0 {
+		r.TaxaFaltas = float64(r.Faltas) / float64(r.Referencias)
+	}
+	return r
+}
+
+// validarPaginacao confere os parâmetros da simulação de paginação
+func validarPaginacao(body PagingBody) error {
+	if body.Frames <= 0 || body.Frames > maxQuadros {
+		return fmt.Errorf("frames deve estar entre 1 e %d", maxQuadros)
+	}
+	if len(body.References) == 0 {
+		return fmt.Errorf("a cadeia de referências está vazia")
+	}
+	if len(body.References) > limites.TempoMaximo {
+		return fmt.Errorf("a cadeia tem %d referências; o limite do servidor é %d", len(body.References), limites.TempoMaximo)
+	}
+	if body.Writes != nil && len(body.Writes) != len(body.References) {
+		return fmt.Errorf("writes deve ter o mesmo tamanho de references")
+	}
+	if body.NruReset < 0 {
+		return fmt.Errorf("nruReset não pode ser negativo")
+	}
+	for i, p := range body.References {
+		if p < 0 {
+			return fmt.Errorf("página inválida na referência %d", i+1)
+		}
+	}
+	if len(body.BeladyFrames) > maxQuadrosBelady {
+		return fmt.Errorf("beladyFrames tem %d quantidades; o limite é %d", len(body.BeladyFrames), maxQuadrosBelady)
+	}
+	for _, q := range body.BeladyFrames {
+		if q <= 0 || q > maxQuadros {
+			return fmt.Errorf("beladyFrames deve ter valores entre 1 e %d", maxQuadros)
+		}
+	}
+	return limites.validar(ContextBody{TimeoutMs: body.TimeoutMs})
+}
+
+// simularPaginacao roda o algoritmo escolhido com uma quantidade de quadros
+func simularPaginacao(ctx context.Context, body PagingBody, quadros int) (ResultadoPaginacao, error) {
+	nruReset := body.NruReset
+	if nruReset == 0 {
+		nruReset = nruResetPadrao
+	}
+
+	s := novoSimuladorPaginacao(quadros, body.References, body.Writes)
+	s.ctx = ctx
+	alg, err := novoSubstituidor(body.Alg, s, nruReset)
+	if err != nil {
+		return ResultadoPaginacao{}, err
+	}
+
+	s.executar(alg)
+
+	// Uma simulação cancelada não tem resultado, nem parcial
+	if err := ctx.Err(); err != nil {
+		return ResultadoPaginacao{}, erroCancelamento(err)
+	}
+	return s.resultado(body.Alg), nil
+}
+
+// processPaging executa a simulação pedida e, se pedida, a verificação da anomalia de Belady
+func processPaging(ctx context.Context, body PagingBody) (ResultadoPaginacao, error) {
+	resultado, err := simularPaginacao(ctx, body, body.Frames)
+	if err != nil {
+		return ResultadoPaginacao{}, err
+	}
+
+	if !body.Belady && len(body.BeladyFrames) == 0 {
+		return resultado, nil
+	}
+	resultado.Belady, err = verificarBelady(ctx, body)
+	if err != nil {
+		return ResultadoPaginacao{}, err
+	}
+	return resultado, nil
+}
+
+// verificarBelady simula o mesmo algoritmo com várias quantidades de quadros e aponta
+// os casos em que um quadro a mais causou mais faltas
+// Sem beladyFrames, testa de 1 até o número de páginas distintas da cadeia
+func verificarBelady(ctx context.Context, body PagingBody) (*AnaliseBelady, error) {
+	quantidades := body.BeladyFrames
+	if len(quantidades) == 0 {
+		distintas := map[int]bool{}
+		for _, p := range body.References {
+			distintas[p] = true
+		}
+		for q := 1; q <= min(len(distintas), maxQuadrosBelady); q++ {
+			quantidades = append(quantidades, q)
+		}
+	}
+
+	analise := &AnaliseBelady{Anomalias: []AnomaliaBelady{}}
+	faltas := map[int]int{}
+	for _, q := range quantidades {
+		r, err := simularPaginacao(ctx, body, q)
+		if err != nil {
+			return nil, err
+		}
+		faltas[q] = r.Faltas
+		analise.Faltas = append(analise.Faltas, FaltasPorQuadros{q, r.Faltas})
+	}
+
+	for _, q := range quantidades {
+		if depois, ok := faltas[q+1]; ok && depois > faltas[q] {
+			analise.Anomalias = append(analise.Anomalias, AnomaliaBelady{q, faltas[q], depois})
+		}
+	}
+	analise.Ocorre = len(analise.Anomalias) > 0
+
+	return analise, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// Cadeia clássica de Belady: o FIFO tem mais faltas com 4 quadros do que com 3
+var cadeiaBelady = []int{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+
+// Cadeia do livro do Silberschatz, usada nos exemplos de FIFO, LRU e OPT com 3 quadros
+var cadeiaSilberschatz = []int{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+
+func TestFaltasDePagina(t *testing.T) {
+	casos := []struct {
+		alg         string
+		quadros     int
+		referencias []int
+		faltas      int
+	}{
+		{"fifo", 3, cadeiaBelady, 9},
+		{"fifo", 4, cadeiaBelady, 10},
+		{"fifo", 3, cadeiaSilberschatz, 15},
+		{"lru", 3, cadeiaSilberschatz, 12},
+		{"opt", 3, cadeiaSilberschatz, 9},
+		{"lru", 3, cadeiaBelady, 10},
+		{"lru", 4, cadeiaBelady, 8},
+		{"opt", 3, cadeiaBelady, 7},
+		{"opt", 4, cadeiaBelady, 6},
+	}
+
+	for _, c := range casos {
+		r, err := simularPaginacao(context.Background(), PagingBody{Alg: c.alg, References: c.referencias}, c.quadros)
+		if err != nil {
+			t.Fatalf("%s com %d quadros: %v", c.alg, c.quadros, err)
+		}
+		if r.Faltas != c.faltas {
+			t.Errorf("%s com %d quadros: %d faltas, esperado %d", c.alg, c.quadros, r.Faltas, c.faltas)
+		}
+		if r.Acertos != len(c.referencias)-c.faltas {
+			t.Errorf("%s com %d quadros: %d acertos, esperado %d", c.alg, c.quadros, r.Acertos, len(c.referencias)-c.faltas)
+		}
+	}
+}
+
+func TestVerificarBelady(t *testing.T) {
+	analise, err := verificarBelady(context.Background(), PagingBody{Alg: "fifo", References: cadeiaBelady, BeladyFrames: []int{3, 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !analise.Ocorre || len(analise.Anomalias) != 1 {
+		t.Fatalf("anomalia de Belady não encontrada: %+v", analise)
+	}
+	if a := analise.Anomalias[0]; a != (AnomaliaBelady{Quadros: 3, FaltasAntes: 9, FaltasDepois: 10}) {
+		t.Errorf("anomalia %+v, esperado 3 quadros com 9 faltas e 10 com 4", a)
+	}
+
+	// O LRU é um algoritmo de pilha e nunca apresenta a anomalia
+	analise, err = verificarBelady(context.Background(), PagingBody{Alg: "lru", References: cadeiaBelady})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if analise.Ocorre {
+		t.Errorf("o LRU não deveria apresentar a anomalia: %+v", analise.Anomalias)
+	}
+	if len(analise.Faltas) != 5 {
+		t.Errorf("sem beladyFrames deveria testar de 1 a 5 quadros, testou %d", len(analise.Faltas))
+	}
+}
+
+func TestProcessPaging(t *testing.T) {
+	casos := []struct {
+		nome   string
+		body   PagingBody
+		belady int // Quantidades testadas na análise de Belady (-1 = sem análise)
+	}{
+		{"sem pedir a análise", PagingBody{Alg: "fifo", Frames: 3, References: cadeiaBelady}, -1},
+		{"belady", PagingBody{Alg: "fifo", Frames: 3, References: cadeiaBelady, Belady: true}, 5},
+		{"beladyFrames", PagingBody{Alg: "fifo", Frames: 3, References: cadeiaBelady, BeladyFrames: []int{3, 4}}, 2},
+	}
+	for _, c := range casos {
+		r, err := processPaging(context.Background(), c.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Faltas != 9 {
+			t.Errorf("%s: %d faltas, esperado 9", c.nome, r.Faltas)
+		}
+		if testadas := -1; r.Belady != nil {
+			testadas = len(r.Belady.Faltas)
+			if testadas != c.belady {
+				t.Errorf("%s: %d quantidades testadas, esperado %d", c.nome, testadas, c.belady)
+			}
+		} else if c.belady != -1 {
+			t.Errorf("%s: análise de Belady ausente", c.nome)
+		}
+	}
+
+	// Cancelada, a simulação não devolve resultado parcial
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+	if _, err := processPaging(ctx, PagingBody{Alg: "lru", Frames: 3, References: cadeiaBelady, Belady: true}); err == nil {
+		t.Error("simulação cancelada devolveu resultado")
+	}
+}
+
+func TestValidarPaginacao(t *testing.T) {
+	valida := PagingBody{Alg: "fifo", Frames: 3, References: cadeiaBelady}
+	casos := []struct {
+		nome  string
+		mudar func(b *PagingBody)
+	}{
+		{"sem quadros", func(b *PagingBody) { b.Frames = 0 }},
+		{"cadeia vazia", func(b *PagingBody) { b.References = nil }},
+		{"writes de outro tamanho", func(b *PagingBody) { b.Writes = []bool{true} }},
+		{"página negativa", func(b *PagingBody) { b.References = []int{1, -2} }},
+		{"quadros de Belady inválidos", func(b *PagingBody) { b.BeladyFrames = []int{0} }},
+		{"quantidades de Belady demais", func(b *PagingBody) { b.BeladyFrames = slices.Repeat([]int{3}, maxQuadrosBelady+1) }},
+		{"timeoutMs negativo", func(b *PagingBody) { b.TimeoutMs = -1 }},
+	}
+
+	if err := validarPaginacao(valida); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range casos {
+		body := valida
+		c.mudar(&body)
+		if err := validarPaginacao(body); err == nil {
+			t.Errorf("%s: deveria ser inválido", c.nome)
+		}
+	}
+}
@@ -1,41 +1,25 @@
 package main
 
-import (
-	"slices"
-)
-
 type PSP struct{
 	s *Simulador
 }
 
+// criteriosPrioridade descreve a ordenação da fila usada pelo PSP e pelo PCPP
+// Sem envelhecimento, a prioridade atual é igual à original
+var criteriosPrioridade = []criterio{criterioPrioridadeAtual}
+
 // adicionarProcessosNovos verifica se há processos novos chegando neste instante, se houver um novo processo, 
 // ordena os processos que não executaram ainda pelo tempo de duracao
 func(alg *PSP) adicionarProcessosNovos(){
 	for _, p := range alg.s.processos{
 		// Se o processo chegou agora
-		if p.instanteCriacao == alg.s.tempoAtual{
+		if alg.s.chegouAgora(p){
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
 		}
 	}
 
-	// Ordena a fila de execução por maior prioridade:
-	slices.SortFunc(alg.s.filaDeExecucao , func(a, b *Processo) int{
-		if a.prioridadeOriginal > b.prioridadeOriginal{
-			return -1
-		} else if a.prioridadeOriginal < b.prioridadeOriginal{
-			return 1
-		} else{
-			if a.tempoRestante <  b.tempoRestante{
-			return -1
-			} else if a.tempoRestante >  b.tempoRestante{
-				return 1
-			} else{
-				return 0
-		}	
-		}
-	})	
-	
-
+	// Ordena a fila de execução por maior prioridade (empates seguem a cadeia de desempate)
+	alg.s.ordenarFila(criteriosPrioridade)
 }
 
 func (alg *PSP) executar(){
@@ -50,18 +34,24 @@ func (alg *PSP) executar(){
 			break // Todos os processos foram finalizados, podemos parar
 		}
 
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
+
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
 			// Registra tempo ocioso no diagrama
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
 
 		// Pega o primeiro processo da fila
+		alg.s.registrarDespacho(criteriosPrioridade)
 		processoAtual := alg.s.filaDeExecucao[0]
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -79,9 +69,11 @@ func (alg *PSP) executar(){
 
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 			// Durante a execução, podem chegar novos processos
 			alg.adicionarProcessosNovos()
@@ -91,6 +83,10 @@ func (alg *PSP) executar(){
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 		}
 	}
 }
\ No newline at end of file
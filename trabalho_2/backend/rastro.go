@@ -0,0 +1,122 @@
+package main
+
+import "fmt"
+
+// CandidatoRastro guarda as chaves de ordenação de um processo presente na fila no momento da decisão
+type CandidatoRastro struct {
+	Processo        string `json:"processo"`
+	TempoRestante   int    `json:"tempoRestante"`
+	PrioridadeAtual int    `json:"prioridadeAtual"`
+	Chegada         int    `json:"chegada"`
+}
+
+// DecisaoEscalonamento explica um despacho ou uma preempção feita pelo escalonador
+type DecisaoEscalonamento struct {
+	Instante   int               `json:"instante"`
+	Tipo       string            `json:"tipo"` // "despacho" ou "preempcao"; com memorySize, também "admissao", "suspensao" e "retorno"; com seções críticas, "bloqueio", "desbloqueio", "heranca", "deadlock" e "recuperacao"; com programas, "bloqueio" e "desbloqueio"; com filhos, "fork", "wait" e "desbloqueio"
+	Candidatos []CandidatoRastro `json:"candidatos,omitempty"`
+	Escolhido  string            `json:"escolhido"`
+	Regra      string            `json:"regra,omitempty"`      // Critério que decidiu a escolha
+	Desempate  bool              `json:"desempate,omitempty"`  // Indica se a regra usada foi um critério de desempate
+	Preemptado string            `json:"preemptado,omitempty"` // Processo que perdeu a CPU
+	Motivo     string            `json:"motivo,omitempty"`
+}
+
+// rotulo devolve o nome do processo usado no diagrama e no rastro
+// Sem nome nem id na carga de trabalho, o processo é identificado por P<id>
+func (p *Processo) rotulo() string {
+	if p.nome != "" {
+		return p.nome
+	}
+	if p.idExterno != "" {
+		return p.idExterno
+	}
+	return fmt.Sprintf("P%d", p.id)
+}
+
+// explicarEscolha descobre qual critério fez o primeiro da fila vencer os demais
+// Retorna o nome da regra e se ela foi usada como desempate (não é critério do algoritmo)
+func (s *Simulador) explicarEscolha(fila []*Processo, principais []criterio) (string, bool) {
+	if len(fila) < 2 {
+		return "único candidato", false
+	}
+	// Filas FIFO (RR) não são reordenadas: a cadeia de desempate só decide a ordem das chegadas simultâneas
+	if principais == nil {
+		return "ordem na fila", false
+	}
+	criterios := s.cadeia(principais)
+
+	vencedor := fila[0]
+	decisivo := -1
+	for _, outro := range fila[1:] {
+		// Procura o primeiro critério em que o vencedor e o outro candidato diferem
+		diferiu := false
+		for i, c := range criterios {
+			if s.comparar(c, vencedor, outro) != 0 {
+				if i > decisivo {
+					decisivo = i
+				}
+				diferiu = true
+				break
+			}
+		}
+
+		// Empate em todos os critérios: quem decidiu foi a posição na fila
+		if !diferiu {
+			decisivo = len(criterios)
+			break
+		}
+	}
+
+	if decisivo == len(criterios) {
+		return "ordem na fila", len(principais) > 0
+	}
+	return criterios[decisivo].nome, decisivo >= len(principais)
+}
+
+// registrarDespacho anota no rastro a escolha do primeiro processo da fila de execução
+// Deve ser chamado com a fila já ordenada e antes de remover o escolhido
+func (s *Simulador) registrarDespacho(principais []criterio) {
+	if !s.rastrear || len(s.filaDeExecucao) == 0 {
+		return
+	}
+
+	candidatos := make([]CandidatoRastro, len(s.filaDeExecucao))
+	for i, p := range s.filaDeExecucao {
+		candidatos[i] = CandidatoRastro{
+			Processo:        p.rotulo(),
+			TempoRestante:   p.tempoRestante,
+			PrioridadeAtual: p.prioridadeAtual,
+			Chegada:         p.instanteCriacao,
+		}
+	}
+
+	regra, desempate := s.explicarEscolha(s.filaDeExecucao, principais)
+	s.rastro = append(s.rastro, DecisaoEscalonamento{
+		Instante:   s.tempoAtual,
+		Tipo:       "despacho",
+		Candidatos: candidatos,
+		Escolhido:  s.filaDeExecucao[0].rotulo(),
+		Regra:      regra,
+		Desempate:  desempate,
+	})
+}
+
+// registrarPreempcao anota no rastro que o processo atual perdeu a CPU e o motivo
+// proximo pode ser nil quando o substituto só será conhecido no próximo despacho
+func (s *Simulador) registrarPreempcao(preemptado, proximo *Processo, motivo string) {
+	if !s.rastrear {
+		return
+	}
+
+	decisao := DecisaoEscalonamento{
+		Instante:   s.tempoAtual,
+		Tipo:       "preempcao",
+		Preemptado: preemptado.rotulo(),
+		Motivo:     motivo,
+	}
+	if proximo != nil {
+		decisao.Escolhido = proximo.rotulo()
+	}
+	s.rastro = append(s.rastro, decisao)
+}
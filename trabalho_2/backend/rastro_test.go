@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExplicarEscolha(t *testing.T) {
+	a := &Processo{id: 1, tempoRestante: 2, instanteCriacao: 0}
+	b := &Processo{id: 2, tempoRestante: 3, instanteCriacao: 0}
+	c := &Processo{id: 3, tempoRestante: 2, instanteCriacao: 1}
+	d := &Processo{id: 4, tempoRestante: 2, instanteCriacao: 0}
+
+	casos := []struct {
+		nome       string
+		desempate  []string
+		fila       []*Processo
+		principais []criterio
+		regra      string
+		eDesempate bool
+	}{
+		{"sozinho", nil, []*Processo{a}, []criterio{criterioTempoRestante}, "único candidato", false},
+		{"fila FIFO", nil, []*Processo{a, b}, nil, "ordem na fila", false},
+		{"menor rajada", nil, []*Processo{a, b}, []criterio{criterioTempoRestante}, "tempoRestante", false},
+		{"empate na rajada, chegou antes", nil, []*Processo{a, c}, []criterio{criterioTempoRestante}, "instanteCriacao", true},
+		{"empate na rajada e na chegada", nil, []*Processo{a, d}, []criterio{criterioTempoRestante}, "id", true},
+		{"sem cadeia de desempate", []string{}, []*Processo{a, d}, []criterio{criterioTempoRestante}, "ordem na fila", true},
+		// O critério mais profundo usado contra algum dos candidatos é o que explica a escolha
+		{"vários candidatos", nil, []*Processo{a, b, c}, []criterio{criterioTempoRestante}, "instanteCriacao", true},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			regras, err := novasRegras(ContextBody{Alg: "sjf", TieBreak: caso.desempate})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := novoSimulador(nil, 1)
+			s.regras = regras
+
+			regra, desempate := s.explicarEscolha(caso.fila, caso.principais)
+			if regra != caso.regra || desempate != caso.eDesempate {
+				t.Errorf("regra %q (desempate %v), esperado %q (desempate %v)", regra, desempate, caso.regra, caso.eDesempate)
+			}
+		})
+	}
+}
+
+func TestRastroSJF(t *testing.T) {
+	body := ContextBody{Alg: "sjf", Quantum: 1, Trace: true, Input: []Processes{
+		{Begin: 0, Duration: 3, Priority: 1},
+		{Begin: 0, Duration: 2, Priority: 1},
+		{Begin: 1, Duration: 2, Priority: 3},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	esperado := []struct {
+		instante   int
+		escolhido  string
+		regra      string
+		candidatos int
+	}{
+		{0, "P2", "tempoRestante", 2},
+		{2, "P3", "tempoRestante", 2},
+		{4, "P1", "único candidato", 1},
+	}
+	if len(r.Rastro) != len(esperado) {
+		t.Fatalf("%d decisões no rastro, esperado %d: %+v", len(r.Rastro), len(esperado), r.Rastro)
+	}
+	for i, e := range esperado {
+		d := r.Rastro[i]
+		if d.Tipo != "despacho" || d.Instante != e.instante || d.Escolhido != e.escolhido || d.Regra != e.regra || len(d.Candidatos) != e.candidatos {
+			t.Errorf("decisão %d: %+v, esperado despacho de %s em %d pela regra %q entre %d candidatos",
+				i, d, e.escolhido, e.instante, e.regra, e.candidatos)
+		}
+	}
+}
+
+func TestRotulo(t *testing.T) {
+	casos := []struct {
+		processo Processo
+		rotulo   string
+	}{
+		{Processo{id: 3}, "P3"},
+		{Processo{id: 3, idExterno: "job-7"}, "job-7"},
+		{Processo{id: 3, idExterno: "job-7", nome: "compilador"}, "compilador"},
+	}
+	for _, c := range casos {
+		if r := c.processo.rotulo(); r != c.rotulo {
+			t.Errorf("rótulo %q, esperado %q", r, c.rotulo)
+		}
+	}
+}
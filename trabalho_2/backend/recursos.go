@@ -0,0 +1,623 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Protocolos de acesso aos recursos compartilhados aceitos no campo lockProtocol
+const (
+	protocoloNenhum  = "none"        // O detentor mantém a própria prioridade (padrão)
+	protocoloHeranca = "inheritance" // O detentor herda a prioridade do processo mais importante bloqueado por ele
+	protocoloTeto    = "ceiling"     // O detentor assume o teto do recurso ao adquiri-lo (teto imediato)
+)
+
+// Recuperação de deadlock aceita no campo deadlockRecovery
+const (
+	recuperacaoNenhuma  = "none"     // Só detecta e informa (padrão)
+	recuperacaoAbortar  = "abort"    // Aborta a vítima, que sai do sistema sem terminar
+	recuperacaoReinicio = "rollback" // Devolve a vítima ao início da execução, de volta à fila de prontos
+)
+
+// Marcação do diagrama para os processos bloqueados esperando um recurso
+const marcaBloqueado = "xx"
+
+// algoritmosComProtocolo são os algoritmos que escolhem pela prioridade, nos quais os protocolos fazem sentido
+var algoritmosComProtocolo = map[string]bool{"psp": true, "pcpp": true, "rrpe": true}
+
+// CriticalSection é uma seção crítica do processo: a partir de Start unidades de execução,
+// o processo usa Amount instâncias do recurso por Duration unidades
+type CriticalSection struct {
+	Resource string `json:"resource"`
+	Start    int    `json:"start"`
+	Duration int    `json:"duration"`
+	Amount   int    `json:"amount,omitempty"` // Instâncias usadas (padrão 1)
+}
+
+// quantidade devolve quantas instâncias a seção crítica usa
+func (secao CriticalSection) quantidade() int {
+	return max(secao.Amount, 1)
+}
+
+// Recurso é um recurso compartilhado com uma ou mais instâncias (com uma, é um mutex)
+type Recurso struct {
+	nome       string
+	instancias int
+	livres     int
+	teto       int               // Prioridade do processo mais importante que usa o recurso
+	posse      map[*Processo]int // Instâncias com cada processo
+	espera     []*Processo       // Processos bloqueados, na ordem em que bloquearam
+	aquisicoes int
+	bloqueio   int // Soma do tempo que os processos passaram bloqueados no recurso
+}
+
+// ControleRecursos controla os recursos da simulação: quem está com cada recurso, quem espera,
+// os intervalos de bloqueio e de inversão de prioridade e os deadlocks encontrados
+type ControleRecursos struct {
+	protocolo   string
+	recuperacao string
+	recursos    map[string]*Recurso
+	nomes       []string // Recursos na ordem em que aparecem na carga
+	bloqueios   []Bloqueio
+	inversoes   []Inversao
+	deadlocks   []DeadlockSimulacao
+	emDeadlock  []*Processo // Conjunto em deadlock da última detecção, para registrar só as mudanças
+	semSaida    bool        // Todos os processos que faltam estão bloqueados; a simulação não avança mais
+}
+
+// Bloqueio é um intervalo em que o processo ficou fora da fila de prontos esperando um recurso
+type Bloqueio struct {
+	Processo string `json:"processo"`
+	Recurso  string `json:"recurso"`
+	Detentor string `json:"detentor"` // Quem estava com o recurso quando o processo bloqueou
+	Inicio   int    `json:"inicio"`
+	Fim      int    `json:"fim"`
+	Aberto   bool   `json:"aberto,omitempty"` // O processo ainda estava bloqueado no fim da simulação
+}
+
+// Inversao é um intervalo de inversão de prioridade sem limite: enquanto o processo esperava o recurso,
+// a CPU ficou com um processo menos importante que não era o detentor (nem quem bloqueava o detentor)
+type Inversao struct {
+	Processo   string `json:"processo"` // Processo bloqueado
+	Recurso    string `json:"recurso"`
+	Detentor   string `json:"detentor"`
+	Executando string `json:"executando"` // Processo de prioridade intermediária que ocupou a CPU
+	Inicio     int    `json:"inicio"`
+	Fim        int    `json:"fim"`
+}
+
+// DeadlockSimulacao é um deadlock encontrado pelo algoritmo de detecção durante a simulação
+type DeadlockSimulacao struct {
+	Instante    int            `json:"instante"`
+	Processos   []string       `json:"processos"`
+	GrafoEspera []ArestaEspera `json:"grafoEspera"`
+	Vitima      string         `json:"vitima,omitempty"` // Processo abortado ou reiniciado na recuperação
+}
+
+// ResumoRecurso resume o uso de um recurso na simulação
+type ResumoRecurso struct {
+	Nome          string   `json:"nome"`
+	Instancias    int      `json:"instancias"`
+	Teto          *int     `json:"teto,omitempty"` // Presente no protocolo "ceiling"
+	Aquisicoes    int      `json:"aquisicoes"`
+	TempoBloqueio int      `json:"tempoBloqueio"`
+	Detentores    []string `json:"detentores,omitempty"` // Quem ainda estava com o recurso no fim da simulação
+}
+
+// ResultadoRecursos resume os bloqueios, as inversões de prioridade e os deadlocks da simulação
+type ResultadoRecursos struct {
+	Protocolo          string              `json:"protocolo"`
+	Recuperacao        string              `json:"recuperacao"`
+	Recursos           []ResumoRecurso     `json:"recursos"`
+	Bloqueios          []Bloqueio          `json:"bloqueios"`
+	Inversoes          []Inversao          `json:"inversoes"`
+	Deadlocks          []DeadlockSimulacao `json:"deadlocks"`
+	TempoBloqueioTotal int                 `json:"tempoBloqueioTotal"`
+	TempoInversaoTotal int                 `json:"tempoInversaoTotal"`
+}
+
+// novoControleRecursos monta os recursos usados pelas seções críticas da carga
+// Sem seções críticas não há controle: nenhum processo bloqueia
+func novoControleRecursos(body ContextBody, processos []*Processo, regras RegrasOrdenacao) (*ControleRecursos, error) {
+	usados := false
+	for _, p := range processos {
+		usados = usados || len(p.secoesCriticas) > 0
+	}
+	if !usados {
+		if body.LockProtocol != "" || body.Resources != nil || body.DeadlockRecovery != "" {
+			return nil, fmt.Errorf("lockProtocol, resources e deadlockRecovery exigem processos com criticalSections")
+		}
+		return nil, nil
+	}
+
+	c := &ControleRecursos{protocolo: body.LockProtocol, recuperacao: body.DeadlockRecovery, recursos: map[string]*Recurso{}}
+	if c.protocolo == "" {
+		c.protocolo = protocoloNenhum
+	}
+	if c.protocolo != protocoloNenhum && c.protocolo != protocoloHeranca && c.protocolo != protocoloTeto {
+		return nil, fmt.Errorf("lockProtocol inválido: use %q, %q ou %q", protocoloNenhum, protocoloHeranca, protocoloTeto)
+	}
+	if c.protocolo != protocoloNenhum && !algoritmosComProtocolo[body.Alg] {
+		return nil, fmt.Errorf("lockProtocol %q só se aplica a psp, pcpp e rrpe", c.protocolo)
+	}
+	if c.recuperacao == "" {
+		c.recuperacao = recuperacaoNenhuma
+	}
+	if c.recuperacao != recuperacaoNenhuma && c.recuperacao != recuperacaoAbortar && c.recuperacao != recuperacaoReinicio {
+		return nil, fmt.Errorf("deadlockRecovery inválido: use %q, %q ou %q", recuperacaoNenhuma, recuperacaoAbortar, recuperacaoReinicio)
+	}
+	for nome, n := range body.Resources {
+		if n <= 0 {
+			return nil, fmt.Errorf("o recurso %q deve ter ao menos uma instância", nome)
+		}
+	}
+
+	// O teto de cada recurso é a prioridade do processo mais importante que o usa
+	for _, p := range processos {
+		for _, secao := range p.secoesCriticas {
+			r, ok := c.recursos[secao.Resource]
+			if !ok {
+				instancias := 1
+				if n, ok := body.Resources[secao.Resource]; ok {
+					instancias = n
+				}
+				r = &Recurso{nome: secao.Resource, instancias: instancias, livres: instancias, teto: p.prioridadeOriginal, posse: map[*Processo]int{}}
+				c.recursos[r.nome] = r
+				c.nomes = append(c.nomes, r.nome)
+			}
+			if secao.quantidade() > r.instancias {
+				return nil, fmt.Errorf("o processo %s pede %d instâncias de %s, que tem %d", p.rotulo(), secao.quantidade(), r.nome, r.instancias)
+			}
+			if (regras.maiorPrimeiro && p.prioridadeOriginal > r.teto) || (!regras.maiorPrimeiro && p.prioridadeOriginal < r.teto) {
+				r.teto = p.prioridadeOriginal
+			}
+		}
+	}
+	for nome := range body.Resources {
+		if _, ok := c.recursos[nome]; !ok {
+			return nil, fmt.Errorf("resources informa %q, que nenhuma seção crítica usa", nome)
+		}
+	}
+	return c, nil
+}
+
+// validarSecoesCriticas confere as seções críticas de um processo
+// Seções de recursos diferentes podem se sobrepor; do mesmo recurso, não
+func validarSecoesCriticas(p Processes) error {
+	for i, secao := range p.CriticalSections {
+		if secao.Resource == "" {
+			return fmt.Errorf("a seção crítica %d não informa o recurso", i+1)
+		}
+		if secao.Start < 0 || secao.Duration <= 0 || secao.Start+secao.Duration > p.Duration {
+			return fmt.Errorf("a seção crítica %d deve ficar dentro da duração do processo", i+1)
+		}
+		if secao.Amount < 0 {
+			return fmt.Errorf("a seção crítica %d pede uma quantidade negativa de instâncias", i+1)
+		}
+		for j, outra := range p.CriticalSections[:i] {
+			if outra.Resource == secao.Resource && secao.Start < outra.Start+outra.Duration && outra.Start < secao.Start+secao.Duration {
+				return fmt.Errorf("as seções críticas %d e %d usam o recurso %q ao mesmo tempo", j+1, i+1, secao.Resource)
+			}
+		}
+	}
+	return nil
+}
+
+// bloquear é chamado antes de o processo executar uma unidade de tempo: cria os filhos e chama wait()
+// (veja forkarEEsperar), avança o programa do processo (veja executarPrograma) e adquire os recursos
+// das seções críticas que começam neste ponto da execução e, se algum não tiver instâncias
+// livres suficientes, tira o processo da CPU até o recurso ser liberado
+// Devolve true se o processo bloqueou; o algoritmo não deve devolvê-lo à fila de prontos
+func (s *Simulador) bloquear(p *Processo) bool {
+	if s.hierarquia != nil && s.forkarEEsperar(p) {
+		return true
+	}
+	if s.sincronizacao != nil && s.executarPrograma(p) {
+		return true
+	}
+
+	c := s.recursos
+	if c == nil {
+		return false
+	}
+
+	executado := p.duracao - p.tempoRestante
+	for _, secao := range p.secoesCriticas {
+		r := c.recursos[secao.Resource]
+		if secao.Start != executado || r.posse[p] > 0 {
+			continue // O processo desbloqueado já recebe o recurso ao ser acordado
+		}
+		if r.livres >= secao.quantidade() {
+			s.adquirir(r, p, secao.quantidade())
+			continue
+		}
+
+		p.bloqueadoEm = r
+		p.pedido = secao.quantidade()
+		p.indiceBloqueio = len(c.bloqueios)
+		r.espera = append(r.espera, p)
+		c.bloqueios = append(c.bloqueios, Bloqueio{Processo: p.rotulo(), Recurso: r.nome, Detentor: s.detentores(r), Inicio: s.tempoAtual, Aberto: true})
+
+		s.foraDaCPU(p)
+		s.registrarRecurso(p, "bloqueio", fmt.Sprintf("recurso %s está com %s", r.nome, s.detentores(r)))
+
+		if c.protocolo == protocoloHeranca {
+			s.propagarHeranca(r, map[*Recurso]bool{})
+		}
+		return true
+	}
+	return false
+}
+
+// adquirir entrega instâncias do recurso ao processo
+func (s *Simulador) adquirir(r *Recurso, p *Processo, quantidade int) {
+	r.posse[p] += quantidade
+	r.livres -= quantidade
+	r.aquisicoes++
+	if s.recursos.protocolo != protocoloNenhum {
+		s.recalcularElevacao(p)
+	}
+}
+
+// detentores devolve os nomes de quem tem instâncias do recurso, na ordem da carga
+func (s *Simulador) detentores(r *Recurso) string {
+	var nomes []string
+	for _, p := range s.processos {
+		if r.posse[p] > 0 {
+			nomes = append(nomes, p.rotulo())
+		}
+	}
+	return strings.Join(nomes, ", ")
+}
+
+// escalonarRecursos contabiliza o instante que terminou, libera os recursos das seções críticas
+// que o processo atual concluiu, acordando quem esperava por eles, e procura deadlocks
+// Deve ser chamada com o relógio já avançado
+func (s *Simulador) escalonarRecursos(processoAtual *Processo) {
+	c := s.recursos
+
+	for _, nome := range c.nomes {
+		r := c.recursos[nome]
+		for _, p := range r.espera {
+			p.tempoBloqueado++
+			r.bloqueio++
+			if processoAtual != nil && !s.bloqueadoPor(p, processoAtual) && s.prioridadeMaiorQue(p.prioridadeOriginal, processoAtual.prioridadeOriginal) {
+				s.registrarInversao(p, processoAtual)
+			}
+		}
+	}
+
+	if processoAtual != nil {
+		executado := processoAtual.duracao - processoAtual.tempoRestante
+		for _, secao := range processoAtual.secoesCriticas {
+			if r := c.recursos[secao.Resource]; r.posse[processoAtual] > 0 && secao.Start+secao.Duration == executado {
+				s.liberar(r, processoAtual)
+			}
+		}
+	}
+
+	s.procurarDeadlock()
+}
+
+// bloqueadoPor indica se q está entre os detentores que impedem p de executar
+// (quem tem o recurso que p espera, quem bloqueia esses detentores e assim por diante)
+func (s *Simulador) bloqueadoPor(p, q *Processo) bool {
+	visitados := map[*Processo]bool{}
+	var busca func(p *Processo) bool
+	busca = func(p *Processo) bool {
+		if p.bloqueadoEm == nil || visitados[p] {
+			return false
+		}
+		visitados[p] = true
+		for detentor := range p.bloqueadoEm.posse {
+			if detentor == q || busca(detentor) {
+				return true
+			}
+		}
+		return false
+	}
+	return busca(p)
+}
+
+// registrarInversao anota o instante que terminou como inversão de prioridade,
+// estendendo o intervalo anterior se ele for contínuo
+func (s *Simulador) registrarInversao(p, executando *Processo) {
+	c := s.recursos
+	r := p.bloqueadoEm
+	inicio := s.tempoAtual - 1
+
+	for i := len(c.inversoes) - 1; i >= 0; i-- {
+		inv := &c.inversoes[i]
+		if inv.Fim == inicio && inv.Processo == p.rotulo() && inv.Executando == executando.rotulo() && inv.Recurso == r.nome {
+			inv.Fim = s.tempoAtual
+			return
+		}
+	}
+	c.inversoes = append(c.inversoes, Inversao{
+		Processo:   p.rotulo(),
+		Recurso:    r.nome,
+		Detentor:   s.detentores(r),
+		Executando: executando.rotulo(),
+		Inicio:     inicio,
+		Fim:        s.tempoAtual,
+	})
+}
+
+// liberar devolve as instâncias que o processo tem do recurso e acorda, em ordem de prioridade,
+// os processos que esperavam e agora cabem nas instâncias livres; empates ficam com quem bloqueou primeiro
+func (s *Simulador) liberar(r *Recurso, detentor *Processo) {
+	c := s.recursos
+	r.livres += r.posse[detentor]
+	delete(r.posse, detentor)
+	if c.protocolo != protocoloNenhum {
+		s.recalcularElevacao(detentor)
+	}
+
+	for {
+		escolhido := -1
+		for i, p := range r.espera {
+			if p.pedido <= r.livres && (escolhido == -1 || s.prioridadeMaiorQue(p.prioridadeAtual, r.espera[escolhido].prioridadeAtual)) {
+				escolhido = i
+			}
+		}
+		if escolhido == -1 {
+			return
+		}
+
+		p := r.espera[escolhido]
+		s.desbloquear(p)
+		s.adquirir(r, p, p.pedido)
+		s.filaDeExecucao = append(s.filaDeExecucao, p)
+		s.registrarRecurso(p, "desbloqueio", fmt.Sprintf("recebeu o recurso %s de %s", r.nome, detentor.rotulo()))
+	}
+}
+
+// desbloquear tira o processo da espera do recurso e fecha o intervalo de bloqueio
+func (s *Simulador) desbloquear(p *Processo) {
+	r := p.bloqueadoEm
+	r.espera = slices.DeleteFunc(r.espera, func(q *Processo) bool { return q == p })
+	p.bloqueadoEm = nil
+
+	b := &s.recursos.bloqueios[p.indiceBloqueio]
+	b.Fim = s.tempoAtual
+	b.Aberto = false
+}
+
+// propagarHeranca eleva a prioridade dos detentores do recurso e, se algum deles também
+// estiver bloqueado, a de quem o bloqueia, até o fim da cadeia
+func (s *Simulador) propagarHeranca(r *Recurso, visitados map[*Recurso]bool) {
+	if visitados[r] {
+		return
+	}
+	visitados[r] = true
+
+	for _, detentor := range s.processos {
+		if r.posse[detentor] == 0 {
+			continue
+		}
+		antes := detentor.prioridadeAtual
+		s.recalcularElevacao(detentor)
+		if detentor.prioridadeAtual != antes {
+			s.registrarRecurso(detentor, "heranca", fmt.Sprintf("prioridade %d -> %d enquanto está com %s", antes, detentor.prioridadeAtual, r.nome))
+		}
+		if detentor.bloqueadoEm != nil {
+			s.propagarHeranca(detentor.bloqueadoEm, visitados)
+		}
+	}
+}
+
+// recalcularElevacao refaz a prioridade que o protocolo dá ao processo pelos recursos que ele detém:
+// o teto de cada recurso ou a prioridade de quem espera por eles
+func (s *Simulador) recalcularElevacao(p *Processo) {
+	c := s.recursos
+	p.elevado = false
+	for _, nome := range c.nomes {
+		r := c.recursos[nome]
+		if r.posse[p] == 0 {
+			continue
+		}
+
+		candidatas := []int{r.teto}
+		if c.protocolo == protocoloHeranca {
+			candidatas = candidatas[:0]
+			for _, q := range r.espera {
+				candidatas = append(candidatas, q.prioridadeAtual)
+			}
+		}
+		for _, prioridade := range candidatas {
+			if !p.elevado || s.prioridadeMaiorQue(prioridade, p.prioridadeElevada) {
+				p.prioridadeElevada = prioridade
+				p.elevado = true
+			}
+		}
+	}
+
+	p.prioridadeAtual = s.prioridadeBase(p)
+	s.manterElevacao(p)
+}
+
+// manterElevacao aplica a prioridade dada pelo protocolo, se ela for maior que a do próprio processo
+func (s *Simulador) manterElevacao(p *Processo) {
+	if p.elevado && s.prioridadeMaiorQue(p.prioridadeElevada, p.prioridadeAtual) {
+		p.prioridadeAtual = p.prioridadeElevada
+	}
+}
+
+// procurarDeadlock aplica o algoritmo de detecção aos processos no sistema, com a alocação de cada
+// recurso e o pedido de quem está bloqueado; com recuperação, escolhe vítimas até desfazer o deadlock
+func (s *Simulador) procurarDeadlock() {
+	c := s.recursos
+
+	// Sem ninguém bloqueado não há deadlock
+	if !slices.ContainsFunc(s.processos, func(p *Processo) bool { return p.bloqueadoEm != nil }) {
+		c.emDeadlock, c.semSaida = nil, false
+		return
+	}
+
+	for {
+		var ativos []*Processo
+		for _, p := range s.processos {
+			if p.instanteCriacao <= s.tempoAtual && p.tempoRestante > 0 {
+				ativos = append(ativos, p)
+			}
+		}
+
+		disponivel := make([]int, len(c.nomes))
+		for k, nome := range c.nomes {
+			disponivel[k] = c.recursos[nome].livres
+		}
+		nomes := make([]string, len(ativos))
+		alocacao := make([][]int, len(ativos))
+		pedido := make([][]int, len(ativos))
+		for i, p := range ativos {
+			nomes[i] = p.rotulo()
+			alocacao[i] = make([]int, len(c.nomes))
+			pedido[i] = make([]int, len(c.nomes))
+			for k, nome := range c.nomes {
+				r := c.recursos[nome]
+				alocacao[i][k] = r.posse[p]
+				if p.bloqueadoEm == r {
+					pedido[i][k] = p.pedido
+				}
+			}
+		}
+
+		_, _, restantes := detectarDeadlock(disponivel, alocacao, pedido)
+		emDeadlock := make([]*Processo, len(restantes))
+		for i, indice := range restantes {
+			emDeadlock[i] = ativos[indice]
+		}
+
+		// Com todos os processos que faltam bloqueados, nada mais muda na simulação
+		c.semSaida = len(ativos) > 0 && !slices.ContainsFunc(ativos, func(p *Processo) bool { return p.bloqueadoEm == nil })
+
+		if len(emDeadlock) > 0 && !slices.Equal(emDeadlock, c.emDeadlock) {
+			d := DeadlockSimulacao{
+				Instante:    s.tempoAtual,
+				GrafoEspera: grafoDeEspera(restantes, alocacao, pedido, nomes, c.nomes),
+			}
+			for _, p := range emDeadlock {
+				d.Processos = append(d.Processos, p.rotulo())
+			}
+			c.deadlocks = append(c.deadlocks, d)
+			s.registrarRecurso(emDeadlock[0], "deadlock", "em deadlock: "+strings.Join(d.Processos, ", "))
+		}
+		c.emDeadlock = emDeadlock
+
+		if len(emDeadlock) == 0 || c.recuperacao == recuperacaoNenhuma {
+			return
+		}
+		vitima := s.escolherVitima(emDeadlock)
+		c.deadlocks[len(c.deadlocks)-1].Vitima = vitima.rotulo()
+		s.recuperar(vitima)
+		c.emDeadlock = nil
+	}
+}
+
+// escolherVitima escolhe o processo em deadlock de menor prioridade; empates ficam com
+// quem executou menos (perde menos trabalho) e depois com quem chegou por último
+func (s *Simulador) escolherVitima(emDeadlock []*Processo) *Processo {
+	vitima := emDeadlock[0]
+	for _, p := range emDeadlock[1:] {
+		executadoP, executadoV := p.duracao-p.tempoRestante, vitima.duracao-vitima.tempoRestante
+		switch {
+		case s.prioridadeMaiorQue(vitima.prioridadeOriginal, p.prioridadeOriginal):
+			vitima = p
+		case p.prioridadeOriginal != vitima.prioridadeOriginal:
+		case executadoP < executadoV || (executadoP == executadoV && p.id > vitima.id):
+			vitima = p
+		}
+	}
+	return vitima
+}
+
+// recuperar desfaz o deadlock pela vítima: ela deixa de esperar, devolve todos os recursos e,
+// conforme a recuperação, sai do sistema sem terminar (devolvendo também a memória) ou volta ao início da execução
+func (s *Simulador) recuperar(vitima *Processo) {
+	c := s.recursos
+	s.desbloquear(vitima)
+	for _, nome := range c.nomes {
+		if r := c.recursos[nome]; r.posse[vitima] > 0 {
+			s.liberar(r, vitima)
+		}
+	}
+
+	if c.recuperacao == recuperacaoAbortar {
+		vitima.tempoRestante = 0
+		vitima.abortado = true
+		s.registrarRecurso(vitima, "recuperacao", "abortado para desfazer o deadlock")
+
+		// O processo abortado sai do sistema e a memória dele já pode receber quem espera
+		if s.memoria != nil && vitima.naMemoria {
+			s.liberarMemoria(vitima)
+			s.admitirEsperando()
+		}
+		return
+	}
+	vitima.trabalhoPerdido += vitima.duracao - vitima.tempoRestante
+	vitima.tempoRestante = vitima.duracao
+	vitima.reinicios++
+	s.filaDeExecucao = append(s.filaDeExecucao, vitima)
+	s.registrarRecurso(vitima, "recuperacao", "reiniciado para desfazer o deadlock")
+}
+
+// registrarRecurso anota no rastro os bloqueios, desbloqueios, heranças de prioridade e deadlocks
+func (s *Simulador) registrarRecurso(p *Processo, tipo, motivo string) {
+	if !s.rastrear {
+		return
+	}
+	s.rastro = append(s.rastro, DecisaoEscalonamento{
+		Instante:  s.tempoAtual,
+		Tipo:      tipo,
+		Escolhido: p.rotulo(),
+		Motivo:    motivo,
+	})
+}
+
+// resultado resume os recursos ao fim da simulação
+func (c *ControleRecursos) resultado(s *Simulador) *ResultadoRecursos {
+	r := &ResultadoRecursos{
+		Protocolo:   c.protocolo,
+		Recuperacao: c.recuperacao,
+		Recursos:    make([]ResumoRecurso, 0, len(c.nomes)),
+		Bloqueios:   c.bloqueios,
+		Inversoes:   c.inversoes,
+		Deadlocks:   c.deadlocks,
+	}
+	if r.Bloqueios == nil {
+		r.Bloqueios = []Bloqueio{}
+	}
+	if r.Inversoes == nil {
+		r.Inversoes = []Inversao{}
+	}
+	if r.Deadlocks == nil {
+		r.Deadlocks = []DeadlockSimulacao{}
+	}
+
+	for _, nome := range c.nomes {
+		recurso := c.recursos[nome]
+		resumo := ResumoRecurso{Nome: nome, Instancias: recurso.instancias, Aquisicoes: recurso.aquisicoes, TempoBloqueio: recurso.bloqueio}
+		if c.protocolo == protocoloTeto {
+			teto := recurso.teto
+			resumo.Teto = &teto
+		}
+		for _, p := range s.processos {
+			if recurso.posse[p] > 0 {
+				resumo.Detentores = append(resumo.Detentores, p.rotulo())
+			}
+		}
+		r.Recursos = append(r.Recursos, resumo)
+		r.TempoBloqueioTotal += recurso.bloqueio
+	}
+
+	for i := range r.Bloqueios {
+		if r.Bloqueios[i].Aberto {
+			r.Bloqueios[i].Fim = s.tempoAtual
+		}
+	}
+	for _, inv := range r.Inversoes {
+		r.TempoInversaoTotal += inv.Fim - inv.Inicio
+	}
+	return r
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// marsPathfinder é o caso clássico de inversão de prioridade: L segura o barramento, H bloqueia nele
+// e M, de prioridade intermediária, ocupa a CPU. L ainda executa 2 unidades depois de liberar o recurso
+func marsPathfinder(protocolo string) ContextBody {
+	return ContextBody{Alg: "pcpp", Quantum: 2, Trace: true, LockProtocol: protocolo, Input: []Processes{
+		{Begin: 0, Duration: 6, Priority: 1, Name: "L", CriticalSections: []CriticalSection{{Resource: "bus", Start: 1, Duration: 3}}},
+		{Begin: 2, Duration: 3, Priority: 5, Name: "H", CriticalSections: []CriticalSection{{Resource: "bus", Start: 1, Duration: 1}}},
+		{Begin: 4, Duration: 5, Priority: 3, Name: "M"},
+	}}
+}
+
+// simularComRecursos executa a simulação como processScheduler, mas devolve também o simulador,
+// para conferir o estado dos processos no fim
+func simularComRecursos(t *testing.T, body ContextBody) (*Simulador, Resultado) {
+	t.Helper()
+	if err := validarEntrada(body); err != nil {
+		t.Fatal(err)
+	}
+	processos, err := lerEntradas(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	regras, err := novasRegras(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := novoSimulador(processos, body.Quantum)
+	s.regras = regras
+	s.rastrear = body.Trace
+	s.horizonte = limites.horizonte(body)
+	s.ctx = context.Background()
+	if s.recursos, err = novoControleRecursos(body, processos, regras); err != nil {
+		t.Fatal(err)
+	}
+	escalonador, err := novoEscalonador(body.Alg, s, body.Aging)
+	if err != nil {
+		t.Fatal(err)
+	}
+	escalonador.executar()
+
+	resultado := s.imprimirResultados()
+	resultado.Recursos = s.recursos.resultado(s)
+	return s, resultado
+}
+
+// termino devolve o instante de término de cada processo pelo nome
+func termino(r Resultado) map[string]int {
+	terminos := map[string]int{}
+	for _, p := range r.Processos {
+		terminos[p.Nome] = p.Termino
+	}
+	return terminos
+}
+
+func TestMarsPathfinder(t *testing.T) {
+	casos := []struct {
+		protocolo string
+		inversoes []Inversao
+		bloqueios []Bloqueio
+		terminos  map[string]int
+	}{
+		{
+			protocolo: protocoloNenhum,
+			inversoes: []Inversao{{Processo: "H", Recurso: "bus", Detentor: "L", Executando: "M", Inicio: 4, Fim: 9}},
+			bloqueios: []Bloqueio{{Processo: "H", Recurso: "bus", Detentor: "L", Inicio: 3, Fim: 10}},
+			terminos:  map[string]int{"L": 14, "H": 12, "M": 9},
+		},
+		{
+			protocolo: protocoloHeranca,
+			inversoes: []Inversao{},
+			bloqueios: []Bloqueio{{Processo: "H", Recurso: "bus", Detentor: "L", Inicio: 3, Fim: 5}},
+			terminos:  map[string]int{"L": 14, "H": 7, "M": 12},
+		},
+		{
+			// Com o teto, L já adquire o barramento com a prioridade de H, que nem chega a bloquear
+			protocolo: protocoloTeto,
+			inversoes: []Inversao{},
+			bloqueios: []Bloqueio{},
+			terminos:  map[string]int{"L": 14, "H": 7, "M": 12},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.protocolo, func(t *testing.T) {
+			s, r := simularComRecursos(t, marsPathfinder(c.protocolo))
+
+			if !slices.Equal(r.Recursos.Inversoes, c.inversoes) {
+				t.Errorf("inversões %+v, esperado %+v", r.Recursos.Inversoes, c.inversoes)
+			}
+			if !slices.Equal(r.Recursos.Bloqueios, c.bloqueios) {
+				t.Errorf("bloqueios %+v, esperado %+v", r.Recursos.Bloqueios, c.bloqueios)
+			}
+			for nome, fim := range c.terminos {
+				if termino(r)[nome] != fim {
+					t.Errorf("%s terminou em %d, esperado %d", nome, termino(r)[nome], fim)
+				}
+			}
+
+			// Ao liberar o barramento, L volta à prioridade original e só termina depois de M
+			for _, p := range s.processos {
+				if p.prioridadeAtual != p.prioridadeOriginal {
+					t.Errorf("%s terminou com prioridade %d, esperado %d", p.rotulo(), p.prioridadeAtual, p.prioridadeOriginal)
+				}
+			}
+		})
+	}
+}
+
+func TestMarsPathfinderHeranca(t *testing.T) {
+	_, r := simularComRecursos(t, marsPathfinder(protocoloHeranca))
+
+	var herancas []DecisaoEscalonamento
+	for _, d := range r.Rastro {
+		if d.Tipo == "heranca" {
+			herancas = append(herancas, d)
+		}
+	}
+	esperado := DecisaoEscalonamento{Instante: 3, Tipo: "heranca", Escolhido: "L", Motivo: "prioridade 1 -> 5 enquanto está com bus"}
+	if len(herancas) != 1 || herancas[0].Instante != esperado.Instante || herancas[0].Escolhido != esperado.Escolhido || herancas[0].Motivo != esperado.Motivo {
+		t.Errorf("heranças %+v, esperado só %+v", herancas, esperado)
+	}
+
+	_, r = simularComRecursos(t, marsPathfinder(protocoloTeto))
+	if teto := r.Recursos.Recursos[0].Teto; teto == nil || *teto != 5 {
+		t.Errorf("teto do barramento %v, esperado 5", teto)
+	}
+}
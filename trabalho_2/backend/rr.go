@@ -9,13 +9,18 @@ package main
 
 // adicionarProcessosNovos verifica se há processos novos chegando neste instante
 func (alg *RR) adicionarProcessosNovos() {
+	novos := make([]*Processo, 0)
 	for _, p := range alg.s.processos {
 		// Se o processo chegou agora e ainda tem tempo restante (primeira vez na fila)
-		if p.instanteCriacao == alg.s.tempoAtual && p.tempoRestante == p.duracao {
-			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
+		if alg.s.chegouAgora(p) && p.tempoRestante == p.duracao {
+			novos = append(novos, p)
 		}
 	}
 
+	// Processos que chegam juntos entram na fila na ordem da cadeia de desempate
+	alg.s.ordenar(novos, nil)
+	alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, novos...)
+
 }
 
 // executar roda a simulação completa do escalonamento
@@ -31,18 +36,24 @@ func (alg *RR) executar() {
 			break // Todos os processos foram finalizados, podemos parar
 		}
 
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
+
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
 			// Registra tempo ocioso no diagrama
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
 
 		// Pega o primeiro processo da fila
+		alg.s.registrarDespacho(nil)
 		processoAtual := alg.s.filaDeExecucao[0]
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -63,9 +74,11 @@ func (alg *RR) executar() {
 
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 			// Durante a execução, podem chegar novos processos
 			alg.adicionarProcessosNovos()
@@ -75,10 +88,15 @@ func (alg *RR) executar() {
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 		}
 
 		// Se o processo ainda tem tempo restante, reinsere na fila
-		if processoAtual.tempoRestante > 0 {
+		if processoAtual.tempoRestante > 0 && !processoAtual.bloqueado() {
+			alg.s.registrarPreempcao(processoAtual, nil, "quantum expirado")
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, processoAtual)
 		}
 	}
@@ -6,14 +6,22 @@
 	aging int
  }
 
+// criteriosRRPE descreve a ordenação da fila usada pelo RRPE (empates mantêm a ordem de chegada na fila)
+var criteriosRRPE = []criterio{criterioPrioridadeAtual}
+
  // adicionarProcessosNovos verifica se há processos novos chegando neste instante
 func (alg *RRPE) adicionarProcessosNovos() {
+	novos := make([]*Processo, 0)
 	for _, p := range alg.s.processos {
 		// Se o processo chegou agora e ainda tem tempo restante (primeira vez na fila)
-		if p.instanteCriacao == alg.s.tempoAtual && p.tempoRestante == p.duracao {
-			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
+		if alg.s.chegouAgora(p) && p.tempoRestante == p.duracao {
+			novos = append(novos, p)
 		}
 	}
+
+	// Processos que chegam juntos entram na fila na ordem da cadeia de desempate
+	alg.s.ordenar(novos, nil)
+	alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, novos...)
 }
 
 
@@ -30,10 +38,14 @@ func (alg *RRPE) executar() {
 			break // Todos os processos foram finalizados
 		}
 
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
+
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
@@ -42,8 +54,10 @@ func (alg *RRPE) executar() {
 		alg.s.ordenarFilaPorPrioridade()
 
 		// Pega o processo de maior prioridade (primeiro da fila ordenada)
+		alg.s.registrarDespacho(criteriosRRPE)
 		processoAtual := alg.s.filaDeExecucao[0]
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -67,9 +81,11 @@ func (alg *RRPE) executar() {
 
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 			// Durante a execução, podem chegar novos processos
 			alg.adicionarProcessosNovos()
@@ -79,17 +95,25 @@ func (alg *RRPE) executar() {
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 		}
 
 		// Se o processo NÃO terminou no quantum
-		if processoAtual.tempoRestante > 0 {
+		if processoAtual.tempoRestante > 0 && !processoAtual.bloqueado() {
+			alg.s.registrarPreempcao(processoAtual, nil, "quantum expirado")
 			// Restaura a prioridade original
-			processoAtual.prioridadeAtual = processoAtual.prioridadeOriginal
+			alg.s.restaurarPrioridade(processoAtual)
 			// Reinsere o processo na fila
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, processoAtual)
 		}
 
 		// Aplica envelhecimento aos processos que ficaram esperando
-		alg.s.aplicarEnvelhecimento(alg.aging)
+		// (nas estratégias medidas em unidades de tempo, isso já acontece a cada instante)
+		if alg.s.envelhecimento.porRodada {
+			alg.s.aplicarEnvelhecimento(alg.aging)
+		}
 	}
 }
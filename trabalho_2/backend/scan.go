@@ -0,0 +1,21 @@
+package main
+
+// SCAN (elevador) atende as requisições no sentido atual até o fim do disco e então inverte o sentido
+type SCAN struct {
+	s *SimuladorDisco
+}
+
+func (alg *SCAN) proximoMovimento() Movimento {
+	s := alg.s
+	if r := s.maisProxima(s.direcao); r != nil {
+		return Movimento{destino: r.cilindro, requisicao: r}
+	}
+
+	// Sem requisições à frente, o braço ainda vai até o último cilindro antes de voltar
+	if fim := s.extremo(s.direcao); s.cabeca != fim {
+		return Movimento{destino: fim}
+	}
+	s.direcao = -s.direcao
+	r := s.maisProxima(s.direcao)
+	return Movimento{destino: r.cilindro, requisicao: r}
+}
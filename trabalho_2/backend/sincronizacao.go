@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Passos aceitos nos programas dos processos
+const (
+	passoComputar = "compute" // Usa a CPU por Duration unidades
+	passoP        = "P"       // wait/down: decrementa o semáforo ou bloqueia até um V
+	passoV        = "V"       // signal/up: acorda o primeiro processo bloqueado ou incrementa o semáforo
+	passoES       = "io"      // Sai da CPU por Duration unidades esperando E/S
+)
+
+// Marcações do diagrama para os processos bloqueados em um semáforo e em E/S
+const (
+	marcaSemaforo = "ss"
+	marcaES       = "io"
+)
+
+// Cenários clássicos de sincronização aceitos no campo scenario
+const (
+	cenarioProdutorConsumidor = "producer-consumer"
+	cenarioLeitoresEscritores = "readers-writers"
+	cenarioFilosofos          = "dining-philosophers"
+)
+
+// ProgramStep é um passo do programa de um processo
+type ProgramStep struct {
+	Op        string `json:"op"`                  // "compute", "P", "V" ou "io"
+	Duration  int    `json:"duration,omitempty"`  // Unidades de tempo de "compute" e "io"
+	Semaphore string `json:"semaphore,omitempty"` // Semáforo de "P" e "V"
+}
+
+// Semaforo é um semáforo contador com fila FIFO de processos bloqueados
+type Semaforo struct {
+	nome       string
+	inicial    int
+	valor      int
+	espera     []*Processo // Processos bloqueados em P, na ordem em que bloquearam
+	operacoesP int
+	operacoesV int
+	bloqueio   int // Soma do tempo que os processos passaram bloqueados no semáforo
+}
+
+// ControleSincronizacao executa os passos dos programas: semáforos e E/S
+type ControleSincronizacao struct {
+	cenario      string
+	semaforos    map[string]*Semaforo
+	nomes        []string // Semáforos em ordem alfabética
+	esperas      []EsperaSemaforo
+	entradaSaida []IntervaloES
+	semSaida     bool // Todos os processos que faltam estão bloqueados em semáforos; a simulação não avança mais
+}
+
+// EsperaSemaforo é um intervalo em que o processo ficou bloqueado em um P
+type EsperaSemaforo struct {
+	Processo string `json:"processo"`
+	Semaforo string `json:"semaforo"`
+	Inicio   int    `json:"inicio"`
+	Fim      int    `json:"fim"`
+	Aberto   bool   `json:"aberto,omitempty"` // O processo ainda estava bloqueado no fim da simulação
+}
+
+// IntervaloES é um intervalo em que o processo ficou fora da CPU esperando E/S
+type IntervaloES struct {
+	Processo string `json:"processo"`
+	Inicio   int    `json:"inicio"`
+	Fim      int    `json:"fim"`
+}
+
+// ResumoSemaforo resume o uso de um semáforo na simulação
+type ResumoSemaforo struct {
+	Nome        string   `json:"nome"`
+	Inicial     int      `json:"inicial"`
+	Final       int      `json:"final"`
+	OperacoesP  int      `json:"operacoesP"`
+	OperacoesV  int      `json:"operacoesV"`
+	TempoEspera int      `json:"tempoEspera"`
+	Esperando   []string `json:"esperando,omitempty"` // Quem ainda estava bloqueado no fim da simulação
+}
+
+// ResultadoSincronizacao resume os semáforos, os bloqueios e as operações de E/S da simulação
+type ResultadoSincronizacao struct {
+	Cenario          string           `json:"cenario,omitempty"`
+	Semaforos        []ResumoSemaforo `json:"semaforos"`
+	Esperas          []EsperaSemaforo `json:"esperas"`
+	EntradaSaida     []IntervaloES    `json:"entradaSaida"`
+	TempoEsperaTotal int              `json:"tempoEsperaTotal"`
+	TempoESTotal     int              `json:"tempoESTotal"`
+	Deadlock         bool             `json:"deadlock"` // A simulação parou com todos os processos restantes bloqueados em semáforos
+}
+
+// aplicarCenario troca a carga pelo cenário pedido em scenario, com seus processos e semáforos
+func aplicarCenario(body ContextBody) (ContextBody, error) {
+	if body.Scenario == "" {
+		return body, nil
+	}
+	if len(body.Input) > 0 || body.Semaphores != nil {
+		return body, fmt.Errorf("scenario já define input e semaphores")
+	}
+
+	var err error
+	body.Input, body.Semaphores, err = novoCenario(body.Scenario)
+	return body, err
+}
+
+// novoCenario monta os processos e os semáforos de um cenário clássico de sincronização
+func novoCenario(nome string) ([]Processes, map[string]int, error) {
+	switch nome {
+	case cenarioProdutorConsumidor:
+		// Buffer de 2 posições; cada produtor produz e cada consumidor consome 2 itens
+		produtor := repetir(2, computar(2), opP("empty"), opP("mutex"), computar(1), opV("mutex"), opV("full"))
+		consumidor := repetir(2, opP("full"), opP("mutex"), computar(1), opV("mutex"), opV("empty"), computar(2))
+		return []Processes{
+			{Name: "produtor1", Begin: 0, Priority: 1, Program: produtor},
+			{Name: "produtor2", Begin: 1, Priority: 1, Program: produtor},
+			{Name: "consumidor1", Begin: 0, Priority: 1, Program: consumidor},
+			{Name: "consumidor2", Begin: 1, Priority: 1, Program: consumidor},
+		}, map[string]int{"mutex": 1, "empty": 2, "full": 0}, nil
+
+	case cenarioLeitoresEscritores:
+		// Até 3 leitores juntos: cada leitor ocupa uma vaga de rw e o escritor ocupa todas,
+		// reservando-as uma a uma sob wmutex para dois escritores não dividirem as vagas
+		leitor := []ProgramStep{computar(1), opP("rw"), computar(3), opV("rw"), computar(1)}
+		escritor := []ProgramStep{computar(1), opP("wmutex"), opP("rw"), opP("rw"), opP("rw"), opV("wmutex"), computar(2), opV("rw"), opV("rw"), opV("rw")}
+		return []Processes{
+			{Name: "leitor1", Begin: 0, Priority: 1, Program: leitor},
+			{Name: "escritor1", Begin: 1, Priority: 1, Program: escritor},
+			{Name: "leitor2", Begin: 2, Priority: 1, Program: leitor},
+			{Name: "escritor2", Begin: 3, Priority: 1, Program: escritor},
+			{Name: "leitor3", Begin: 4, Priority: 1, Program: leitor},
+		}, map[string]int{"rw": 3, "wmutex": 1}, nil
+
+	case cenarioFilosofos:
+		// 5 filósofos pensam e comem 2 vezes (comer leva mais que um quantum pequeno); o último pega os garfos na ordem inversa, o que evita o deadlock
+		const n = 5
+		var processos []Processes
+		semaforos := map[string]int{}
+		for i := 1; i <= n; i++ {
+			esquerdo, direito := fmt.Sprintf("garfo%d", i), fmt.Sprintf("garfo%d", i%n+1)
+			if i == n {
+				esquerdo, direito = direito, esquerdo
+			}
+			semaforos[esquerdo], semaforos[direito] = 1, 1
+			processos = append(processos, Processes{
+				Name:     fmt.Sprintf("filosofo%d", i),
+				Priority: 1,
+				Program:  repetir(2, computar(1), opP(esquerdo), opP(direito), computar(3), opV(direito), opV(esquerdo)),
+			})
+		}
+		return processos, semaforos, nil
+	}
+	return nil, nil, fmt.Errorf("scenario inválido: use %q, %q ou %q", cenarioProdutorConsumidor, cenarioLeitoresEscritores, cenarioFilosofos)
+}
+
+// Atalhos para montar os programas dos cenários
+func computar(n int) ProgramStep      { return ProgramStep{Op: passoComputar, Duration: n} }
+func opP(semaforo string) ProgramStep { return ProgramStep{Op: passoP, Semaphore: semaforo} }
+func opV(semaforo string) ProgramStep { return ProgramStep{Op: passoV, Semaphore: semaforo} }
+
+// repetir devolve os passos repetidos n vezes
+func repetir(n int, passos ...ProgramStep) []ProgramStep {
+	var programa []ProgramStep
+	for range n {
+		programa = append(programa, passos...)
+	}
+	return programa
+}
+
+// duracaoDaEntrada devolve a duração do processo: com um programa, é a soma dos passos "compute";
+// com threads, a soma das rajadas das threads
+func duracaoDaEntrada(p Processes) int {
+	soma := 0
+	switch {
+	case len(p.Program) > 0:
+		for _, passo := range p.Program {
+			if passo.Op == passoComputar {
+				soma += passo.Duration
+			}
+		}
+	case len(p.Threads) > 0:
+		for _, t := range p.Threads {
+			soma += t.Duration
+		}
+	default:
+		soma = p.Duration
+	}
+	return soma
+}
+
+// validarProgramas confere os programas dos processos e os semáforos que eles usam
+func validarProgramas(body ContextBody) error {
+	for nome, valor := range body.Semaphores {
+		if valor < 0 {
+			return fmt.Errorf("o semáforo %q não pode começar negativo", nome)
+		}
+	}
+
+	usados := false
+	for i, entrada := range body.Input {
+		if len(entrada.Program) == 0 {
+			continue
+		}
+		usados = true
+		if err := validarPrograma(entrada, body.Semaphores); err != nil {
+			return fmt.Errorf("Processo %d: %v", i+1, err)
+		}
+	}
+	if !usados && body.Semaphores != nil {
+		return fmt.Errorf("semaphores exige processos com program")
+	}
+	return nil
+}
+
+// validarPrograma confere os passos do programa de um processo
+// Depois do último "compute" só pode haver V, executados assim que o processo termina de computar
+func validarPrograma(p Processes, semaforos map[string]int) error {
+	if len(p.CriticalSections) > 0 {
+		return fmt.Errorf("program e criticalSections não podem ser usados juntos")
+	}
+	if p.Duration != 0 && p.Duration != duracaoDaEntrada(p) {
+		return fmt.Errorf("duration deve ser omitida ou igual à soma dos passos compute (%d)", duracaoDaEntrada(p))
+	}
+	if duracaoDaEntrada(p) == 0 {
+		return fmt.Errorf("o programa precisa de ao menos um passo compute")
+	}
+
+	computou := false
+	for i, passo := range slices.Backward(p.Program) {
+		switch passo.Op {
+		case passoComputar, passoES:
+			if passo.Duration <= 0 || passo.Semaphore != "" {
+				return fmt.Errorf("o passo %d (%s) precisa de duration positiva e não usa semaphore", i+1, passo.Op)
+			}
+		case passoP, passoV:
+			if _, ok := semaforos[passo.Semaphore]; !ok {
+				return fmt.Errorf("o passo %d (%s) usa o semáforo %q, que não está em semaphores", i+1, passo.Op, passo.Semaphore)
+			}
+			if passo.Duration != 0 {
+				return fmt.Errorf("o passo %d (%s) não tem duration", i+1, passo.Op)
+			}
+		default:
+			return fmt.Errorf("o passo %d tem op inválida: use %q, %q, %q ou %q", i+1, passoComputar, passoP, passoV, passoES)
+		}
+
+		computou = computou || passo.Op == passoComputar
+		if !computou && passo.Op != passoV {
+			return fmt.Errorf("o passo %d (%s) vem depois do último compute; ali só pode haver V", i+1, passo.Op)
+		}
+	}
+	return nil
+}
+
+// novoControleSincronizacao monta os semáforos usados pelos programas da carga
+// Sem programas não há controle: os processos são rajadas únicas de CPU
+func novoControleSincronizacao(body ContextBody, processos []*Processo) *ControleSincronizacao {
+	if !slices.ContainsFunc(processos, func(p *Processo) bool { return len(p.programa) > 0 }) {
+		return nil
+	}
+
+	c := &ControleSincronizacao{cenario: body.Scenario, semaforos: map[string]*Semaforo{}}
+	for nome, valor := range body.Semaphores {
+		c.semaforos[nome] = &Semaforo{nome: nome, inicial: valor, valor: valor}
+		c.nomes = append(c.nomes, nome)
+	}
+	slices.Sort(c.nomes)
+	return c
+}
+
+// bloqueado indica se o processo está fora da fila de prontos esperando um recurso, um semáforo, E/S ou os filhos
+func (p *Processo) bloqueado() bool {
+	return p.bloqueadoEm != nil || p.esperandoSemaforo != nil || p.emES || p.esperandoFilhos
+}
+
+// executarPrograma é chamado antes de o processo executar uma unidade de tempo: executa os passos
+// P e V que vêm antes do próximo "compute" e, se um P encontrar o semáforo em zero ou vier uma E/S,
+// tira o processo da CPU
+// Devolve true se o processo bloqueou; o algoritmo não deve devolvê-lo à fila de prontos
+func (s *Simulador) executarPrograma(p *Processo) bool {
+	c := s.sincronizacao
+	for p.passo < len(p.programa) {
+		passo := p.programa[p.passo]
+		switch passo.Op {
+		case passoComputar:
+			return false
+
+		case passoV:
+			s.sinalizar(c.semaforos[passo.Semaphore], p)
+			p.passo++
+
+		case passoP:
+			sem := c.semaforos[passo.Semaphore]
+			sem.operacoesP++
+			if sem.valor > 0 {
+				sem.valor--
+				p.passo++
+				continue
+			}
+
+			p.esperandoSemaforo = sem
+			p.indiceEspera = len(c.esperas)
+			sem.espera = append(sem.espera, p)
+			c.esperas = append(c.esperas, EsperaSemaforo{Processo: p.rotulo(), Semaforo: sem.nome, Inicio: s.tempoAtual, Aberto: true})
+			s.foraDaCPU(p)
+			s.registrarRecurso(p, "bloqueio", fmt.Sprintf("P(%s) com o semáforo em 0", sem.nome))
+			return true
+
+		case passoES:
+			p.emES = true
+			c.entradaSaida = append(c.entradaSaida, IntervaloES{Processo: p.rotulo(), Inicio: s.tempoAtual, Fim: s.tempoAtual + passo.Duration})
+			s.foraDaCPU(p)
+			s.registrarRecurso(p, "bloqueio", fmt.Sprintf("E/S por %d unidades", passo.Duration))
+			return true
+		}
+	}
+	return false
+}
+
+// foraDaCPU ajusta o processo que bloqueou antes de executar pela primeira vez: ele ainda não começou
+// Quem voltou ao início num rollback já tinha começado antes e mantém o instante de início
+func (s *Simulador) foraDaCPU(p *Processo) {
+	if p.tempoRestante == p.duracao && p.tempoInicio == s.tempoAtual {
+		p.tempoInicio = -1
+	}
+}
+
+// sinalizar executa V: acorda o primeiro processo bloqueado no semáforo, que completa o seu P,
+// ou incrementa o semáforo se ninguém espera
+func (s *Simulador) sinalizar(sem *Semaforo, quem *Processo) {
+	c := s.sincronizacao
+	sem.operacoesV++
+	if len(sem.espera) == 0 {
+		sem.valor++
+		return
+	}
+
+	acordado := sem.espera[0]
+	sem.espera = sem.espera[1:]
+	acordado.esperandoSemaforo = nil
+	acordado.passo++
+	e := &c.esperas[acordado.indiceEspera]
+	e.Fim = s.tempoAtual
+	e.Aberto = false
+	s.filaDeExecucao = append(s.filaDeExecucao, acordado)
+	s.registrarRecurso(acordado, "desbloqueio", fmt.Sprintf("V(%s) feito por %s", sem.nome, quem.rotulo()))
+}
+
+// escalonarSincronizacao contabiliza o instante que terminou, devolve à fila quem concluiu a E/S
+// e avança o programa do processo atual, executando os V que seguem um "compute" concluído
+// Deve ser chamada com o relógio já avançado
+func (s *Simulador) escalonarSincronizacao(processoAtual *Processo) {
+	c := s.sincronizacao
+
+	for _, p := range s.processos {
+		switch {
+		case p.esperandoSemaforo != nil:
+			p.tempoSemaforo++
+			p.esperandoSemaforo.bloqueio++
+		case p.emES:
+			p.tempoES++
+			p.feitoNoPasso++
+			if p.feitoNoPasso == p.programa[p.passo].Duration {
+				p.emES = false
+				p.passo++
+				p.feitoNoPasso = 0
+				s.filaDeExecucao = append(s.filaDeExecucao, p)
+				s.registrarRecurso(p, "desbloqueio", "E/S concluída")
+			}
+		}
+	}
+
+	if processoAtual != nil && processoAtual.passo < len(processoAtual.programa) {
+		processoAtual.feitoNoPasso++
+		if processoAtual.feitoNoPasso == processoAtual.programa[processoAtual.passo].Duration {
+			processoAtual.passo++
+			processoAtual.feitoNoPasso = 0
+			for processoAtual.passo < len(processoAtual.programa) && processoAtual.programa[processoAtual.passo].Op == passoV {
+				s.sinalizar(c.semaforos[processoAtual.programa[processoAtual.passo].Semaphore], processoAtual)
+				processoAtual.passo++
+			}
+		}
+	}
+
+	// Com todos os processos que faltam bloqueados em semáforos, ninguém mais faz V
+	ativos := 0
+	bloqueados := 0
+	for _, p := range s.processos {
+		if p.instanteCriacao <= s.tempoAtual && p.tempoRestante > 0 {
+			ativos++
+			if p.esperandoSemaforo != nil {
+				bloqueados++
+			}
+		}
+	}
+	c.semSaida = ativos > 0 && ativos == bloqueados && !slices.ContainsFunc(s.processos, func(p *Processo) bool { return p.instanteCriacao > s.tempoAtual })
+}
+
+// resultado resume os semáforos ao fim da simulação
+func (c *ControleSincronizacao) resultado(s *Simulador) *ResultadoSincronizacao {
+	r := &ResultadoSincronizacao{
+		Cenario:      c.cenario,
+		Semaforos:    make([]ResumoSemaforo, 0, len(c.nomes)),
+		Esperas:      c.esperas,
+		EntradaSaida: c.entradaSaida,
+		Deadlock:     c.semSaida,
+	}
+	if r.Esperas == nil {
+		r.Esperas = []EsperaSemaforo{}
+	}
+	if r.EntradaSaida == nil {
+		r.EntradaSaida = []IntervaloES{}
+	}
+
+	for _, nome := range c.nomes {
+		sem := c.semaforos[nome]
+		resumo := ResumoSemaforo{
+			Nome:        nome,
+			Inicial:     sem.inicial,
+			Final:       sem.valor,
+			OperacoesP:  sem.operacoesP,
+			OperacoesV:  sem.operacoesV,
+			TempoEspera: sem.bloqueio,
+		}
+		for _, p := range sem.espera {
+			resumo.Esperando = append(resumo.Esperando, p.rotulo())
+		}
+		r.Semaforos = append(r.Semaforos, resumo)
+		r.TempoEsperaTotal += sem.bloqueio
+	}
+
+	for i := range r.Esperas {
+		if r.Esperas[i].Aberto {
+			r.Esperas[i].Fim = s.tempoAtual
+		}
+	}
+	for i := range r.EntradaSaida {
+		r.EntradaSaida[i].Fim = min(r.EntradaSaida[i].Fim, s.tempoAtual)
+		r.TempoESTotal += r.EntradaSaida[i].Fim - r.EntradaSaida[i].Inicio
+	}
+	return r
+}
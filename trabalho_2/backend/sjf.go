@@ -1,9 +1,5 @@
 package main
 
-import (
-	"sort"
-)
-
 type SJF struct{
 	s *Simulador
 }
@@ -12,15 +8,13 @@ type SJF struct{
 func (alg *SJF) adicionarProcessosNovos() {
 	for _, p := range alg.s.processos {
 		// Se o processo chegou agora
-		if p.instanteCriacao == alg.s.tempoAtual {
+		if alg.s.chegouAgora(p) {
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
 		}
 	}
 
-	// Ordena a fila de execução pelo tempo restante
-	sort.Slice(alg.s.filaDeExecucao, func(i, j int) bool {
-		return alg.s.filaDeExecucao[i].tempoRestante < alg.s.filaDeExecucao[j].tempoRestante
-	})
+	// Ordena a fila de execução pelo tempo restante (empates seguem a cadeia de desempate)
+	alg.s.ordenarFila(alg.s.criteriosTempoRestante())
 }
 
 
@@ -36,19 +30,25 @@ func (alg *SJF) executar() {
 			break // Todos os processos foram finalizados, podemos parar
 		}
 
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
+
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
 			// Registra tempo ocioso no diagrama
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
 		
 		// Pega o primeiro processo da fila
+		alg.s.registrarDespacho(alg.s.criteriosTempoRestante())
 		processoAtual := alg.s.filaDeExecucao[0]
 
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -67,9 +67,11 @@ func (alg *SJF) executar() {
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
 			
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 			// // Durante a execução, podem chegar novos processos
 			alg.adicionarProcessosNovos()
@@ -79,6 +81,10 @@ func (alg *SJF) executar() {
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 		}
 	}
 }
@@ -1,7 +1,7 @@
  package main
 
  import (
- 	"sort"
+ 	"fmt"
  )
 
 
@@ -9,20 +9,17 @@ type SRTF struct{
 	s *Simulador
  }
 
-
 // adicionarProcessosNovos verifica se há processos novos chegando neste instante, se houver um novo processo, ordena os processos que não executaram ainda pelo tempo de duracao
 func (alg *SRTF) adicionarProcessosNovos() {
 	for _, p := range alg.s.processos {
 		// Se o processo chegou agora 
-		if p.instanteCriacao == alg.s.tempoAtual {
+		if alg.s.chegouAgora(p) {
 			alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, p)
 		}
 	}
 
-	// Ordena a fila de execução pelo tempo restante
-	sort.Slice(alg.s.filaDeExecucao, func(i, j int) bool {
-		return alg.s.filaDeExecucao[i].tempoRestante < alg.s.filaDeExecucao[j].tempoRestante
-	})
+	// Ordena a fila de execução pelo tempo restante (empates seguem a cadeia de desempate)
+	alg.s.ordenarFila(alg.s.criteriosTempoRestante())
 }
 
 
@@ -40,18 +37,24 @@ func (alg *SRTF) executar() {
 			break // Todos os processos foram finalizados, podemos parar
 		}
 
+		// No horizonte da simulação, para e deixa os processos restantes como não concluídos
+		if alg.s.interrompido() {
+			break
+		}
+
 		// Se não há processos na fila, mas ainda há processos pendentes, avança o tempo
 		if len(alg.s.filaDeExecucao) == 0 {
 			// Registra tempo ocioso no diagrama
-			alg.s.registrarDiagrama(nil)
-			alg.s.tempoAtual++
+			alg.s.avancarTempo(nil)
 			alg.adicionarProcessosNovos()
 			continue
 		}
 
 		// Pega o primeiro processo da fila
+		alg.s.registrarDespacho(alg.s.criteriosTempoRestante())
 		processoAtual := alg.s.filaDeExecucao[0]
 		alg.s.filaDeExecucao = alg.s.filaDeExecucao[1:] // Remove da fila
+		alg.s.aoDespachar(processoAtual)
 
 		// Marca quando o processo iniciou pela primeira vez
 		if processoAtual.tempoInicio == -1 {
@@ -72,9 +75,11 @@ func (alg *SRTF) executar() {
 
 		// Executa o processo por tempoExecucao unidades de tempo
 		for i := 0; i < tempoExecucao; i++ {
-			alg.s.registrarDiagrama(processoAtual)
-			alg.s.tempoAtual++
-			processoAtual.tempoRestante--
+			// Um processo que encontra o recurso da seção crítica ocupado sai da CPU e da fila de prontos
+			if alg.s.bloquear(processoAtual) {
+				break
+			}
+			alg.s.avancarTempo(processoAtual)
 
 			// Durante a execução, podem chegar novos processos
 			alg.adicionarProcessosNovos()
@@ -84,15 +89,20 @@ func (alg *SRTF) executar() {
 				processoAtual.tempoTermino = alg.s.tempoAtual
 				break
 			}
+
+			if alg.s.interrompido() {
+				return
+			}
 			
 			// Preempta se houver um processo com tempo restante menor chegando
-			if len(alg.s.filaDeExecucao) > 0 && alg.s.filaDeExecucao[0].tempoRestante < processoAtual.tempoRestante {
+			if len(alg.s.filaDeExecucao) > 0 && alg.s.comparar(alg.s.criteriosTempoRestante()[0], alg.s.filaDeExecucao[0], processoAtual) < 0 {
+				alg.s.registrarPreempcao(processoAtual, alg.s.filaDeExecucao[0],
+					fmt.Sprintf("chegou processo com tempo restante menor (%d < %d)", alg.s.filaDeExecucao[0].tempoRestante, processoAtual.tempoRestante))
+
 				// Coloca o processo atual de volta na fila
 				alg.s.filaDeExecucao = append(alg.s.filaDeExecucao, processoAtual)
 				// Reordena a fila
-				sort.Slice(alg.s.filaDeExecucao, func(i, j int) bool {
-					return alg.s.filaDeExecucao[i].tempoRestante < alg.s.filaDeExecucao[j].tempoRestante
-				})
+				alg.s.ordenarFila(alg.s.criteriosTempoRestante())
 				break // Sai do loop para preemptar
 			}
 		}
@@ -0,0 +1,11 @@
+package main
+
+// SSTF atende a requisição pendente mais próxima do braço, em qualquer sentido
+type SSTF struct {
+	s *SimuladorDisco
+}
+
+func (alg *SSTF) proximoMovimento() Movimento {
+	r := alg.s.maisProxima(0)
+	return Movimento{destino: r.cilindro, requisicao: r}
+}
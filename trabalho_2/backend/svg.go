@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"unicode/utf8"
+)
+
+// SVGGantt desenha a linha do tempo como um gráfico de Gantt em SVG independente
+type SVGGantt struct{}
+
+// Dimensões do desenho, em pixels
+const (
+	svgMargem        = 16
+	svgLarguraRotulo = 64
+	svgAlturaLinha   = 22
+	svgEspacoLinhas  = 6
+	svgLarguraMaxima = 1200 // Largura máxima da área do gráfico
+	svgUnidadeMaxima = 28   // Largura máxima de uma unidade de tempo
+	svgUnidadeMinima = 4    // Largura mínima de uma unidade de tempo
+)
+
+// Cores usadas para cada estado e para as trocas de contexto
+var coresSVG = map[string]string{
+	estadoExecutando:    "#3b82f6",
+	estadoEsperando:     "#fcd34d",
+	estadoOcioso:        "#d1d5db",
+	estadoForaDaMemoria: "#c4b5fd",
+	estadoBloqueado:     "#f87171",
+	estadoSemaforo:      "#fb923c",
+	estadoES:            "#34d399",
+	estadoEsperaFilhos:  "#f472b6",
+	"troca":             "#dc2626",
+}
+
+func (e *SVGGantt) tipoConteudo() string { return "image/svg+xml" }
+func (e *SVGGantt) extensao() string     { return "svg" }
+
+// exportar desenha uma linha por processo, uma linha para a CPU com os intervalos ociosos
+// e as trocas de contexto, o eixo do tempo e a legenda
+func (e *SVGGantt) exportar(r Resultado) ([]byte, error) {
+	linha := linhaDoTempoDoResultado(r)
+	fim := max(linha.Fim, 1)
+
+	// Escolhe a largura da unidade para caber na largura máxima
+	unidade := min(max(svgLarguraMaxima/fim, svgUnidadeMinima), svgUnidadeMaxima)
+
+	// Uma linha por processo e a linha da CPU por último
+	linhaCPU := len(linha.Trilhas)
+	alturaGrafico := (linhaCPU + 1) * (svgAlturaLinha + svgEspacoLinhas)
+	yEixo := svgMargem + alturaGrafico
+	yLegenda := yEixo + 36
+	largura := svgMargem*2 + svgLarguraRotulo + fim*unidade
+	altura := yLegenda + svgAlturaLinha + svgMargem
+
+	// Posição de cada instante e de cada linha no desenho
+	x := func(t int) int { return svgMargem + svgLarguraRotulo + t*unidade }
+	y := func(i int) int { return svgMargem + i*(svgAlturaLinha+svgEspacoLinhas) }
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="monospace" font-size="12">`+"\n", largura, altura, largura, altura)
+	b.WriteString(`<defs><pattern id="ocioso" width="6" height="6" patternUnits="userSpaceOnUse" patternTransform="rotate(45)">` +
+		`<rect width="6" height="6" fill="` + coresSVG[estadoOcioso] + `"/><line x1="0" y1="0" x2="0" y2="6" stroke="#9ca3af" stroke-width="2"/></pattern></defs>` + "\n")
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", largura, altura)
+
+	// Rótulos das linhas
+	for i, nome := range linha.Trilhas {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", svgMargem, y(i)+svgAlturaLinha/2, html.EscapeString(nome))
+	}
+	fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle" font-weight="bold">CPU</text>`+"\n", svgMargem, y(linhaCPU)+svgAlturaLinha/2)
+
+	// Segmentos de execução e espera de cada processo
+	for _, seg := range linha.Segmentos {
+		if seg.Trilha < 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="white"><title>%s %s [%d, %d)</title></rect>`+"\n",
+			x(seg.Inicio), y(seg.Trilha), (seg.Fim-seg.Inicio)*unidade, svgAlturaLinha, coresSVG[seg.Estado],
+			html.EscapeString(linha.Trilhas[seg.Trilha]), seg.Estado, seg.Inicio, seg.Fim)
+	}
+
+	// Linha da CPU: quem executou em cada intervalo e os intervalos ociosos
+	cpu := linha.execucaoNaCPU()
+	for i, seg := range cpu {
+		if seg.Estado == estadoOcioso {
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="url(#ocioso)" stroke="white"><title>ocioso [%d, %d)</title></rect>`+"\n",
+				x(seg.Inicio), y(linhaCPU), (seg.Fim-seg.Inicio)*unidade, svgAlturaLinha, seg.Inicio, seg.Fim)
+			continue
+		}
+
+		nome := html.EscapeString(linha.Trilhas[seg.Trilha])
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="white"><title>%s [%d, %d)</title></rect>`+"\n",
+			x(seg.Inicio), y(linhaCPU), (seg.Fim-seg.Inicio)*unidade, svgAlturaLinha, coresSVG[estadoExecutando], nome, seg.Inicio, seg.Fim)
+		if (seg.Fim-seg.Inicio)*unidade >= len(nome)*8 {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" fill="white" text-anchor="middle" dominant-baseline="middle">%s</text>`+"\n",
+				(x(seg.Inicio)+x(seg.Fim))/2, y(linhaCPU)+svgAlturaLinha/2, nome)
+		}
+
+		// Troca de contexto: a CPU passa de um processo para outro (passando ou não por ociosidade)
+		if anterior := execucaoAnterior(cpu, i); anterior != nil && anterior.Trilha != seg.Trilha {
+			fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="4 2"><title>troca de contexto em %d</title></line>`+"\n",
+				x(seg.Inicio), svgMargem, x(seg.Inicio), yEixo, coresSVG["troca"], seg.Inicio)
+		}
+	}
+
+	// Eixo do tempo, com marcações espaçadas para não sobrepor os números
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", x(0), yEixo, x(fim), yEixo)
+	passo := 1
+	for passo*unidade < 24 {
+		passo++
+	}
+	for t := 0; t <= fim; t++ {
+		if t%passo != 0 && t != fim {
+			continue
+		}
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", x(t), yEixo, x(t), yEixo+4)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%d</text>`+"\n", x(t), yEixo+16, t)
+	}
+
+	// Legenda
+	itens := []struct{ nome, cor string }{
+		{estadoExecutando, coresSVG[estadoExecutando]},
+		{estadoEsperando, coresSVG[estadoEsperando]},
+		{estadoOcioso, "url(#ocioso)"},
+	}
+	for _, estado := range []string{estadoForaDaMemoria, estadoBloqueado, estadoSemaforo, estadoES, estadoEsperaFilhos} {
+		if linha.temEstado(estado) {
+			itens = append(itens, struct{ nome, cor string }{estado, coresSVG[estado]})
+		}
+	}
+	xLegenda := svgMargem
+	for _, item := range itens {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="14" height="14" fill="%s" stroke="#6b7280"/>`+"\n", xLegenda, yLegenda, item.cor)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", xLegenda+20, yLegenda+7, item.nome)
+		xLegenda += max(120, 28+8*utf8.RuneCountInString(item.nome))
+	}
+	fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2" stroke-dasharray="4 2"/>`+"\n",
+		xLegenda+7, yLegenda, xLegenda+7, yLegenda+14, coresSVG["troca"])
+	fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">troca de contexto (%d)</text>`+"\n", xLegenda+20, yLegenda+7, r.TrocasContexto)
+
+	b.WriteString("</svg>\n")
+	return b.Bytes(), nil
+}
+
+// execucaoAnterior devolve o último segmento de execução antes da posição i na linha da CPU
+func execucaoAnterior(cpu []Segmento, i int) *Segmento {
+	for j := i - 1; j >= 0; j-- {
+		if cpu[j].Estado == estadoExecutando {
+			return &cpu[j]
+		}
+	}
+	return nil
+}
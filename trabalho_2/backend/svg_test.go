@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// elementoSVG é um elemento do SVG gerado, com os atributos e os filhos, para as verificações
+type elementoSVG struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr    `xml:",any,attr"`
+	Texto   string        `xml:",chardata"`
+	Filhos  []elementoSVG `xml:",any"`
+}
+
+// atributo devolve o valor do atributo do elemento
+func (e elementoSVG) atributo(nome string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == nome {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// titulos devolve os títulos (dicas) dos elementos com o nome informado
+func (e elementoSVG) titulos(nome string) []string {
+	var titulos []string
+	for _, f := range e.Filhos {
+		if f.XMLName.Local != nome {
+			continue
+		}
+		for _, g := range f.Filhos {
+			if g.XMLName.Local == "title" {
+				titulos = append(titulos, g.Texto)
+			}
+		}
+	}
+	return titulos
+}
+
+func TestSVGGantt(t *testing.T) {
+	r := resultadoDeTeste()
+	r.OrdemProcessos[1] = "<B> "
+	r.TrocasContexto = 2
+
+	saida, err := (&SVGGantt{}).exportar(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var svg elementoSVG
+	if err := xml.Unmarshal(saida, &svg); err != nil {
+		t.Fatalf("SVG inválido: %v\n%s", err, saida)
+	}
+
+	// 5 instantes de 28 px, mais as margens e a coluna dos rótulos
+	if svg.atributo("width") != "236" {
+		t.Errorf("largura %s, esperado 236", svg.atributo("width"))
+	}
+
+	retangulos := []string{
+		"A executando [0, 1)", "A esperando [1, 3)", "A executando [4, 5)", "<B> executando [1, 3)",
+		"A [0, 1)", "<B> [1, 3)", "ocioso [3, 4)", "A [4, 5)",
+	}
+	if titulos := svg.titulos("rect"); !slices.Equal(titulos, retangulos) {
+		t.Errorf("retângulos %q, esperado %q", titulos, retangulos)
+	}
+	if titulos := svg.titulos("line"); !slices.Equal(titulos, []string{"troca de contexto em 1", "troca de contexto em 4"}) {
+		t.Errorf("trocas de contexto %q", titulos)
+	}
+
+	// A legenda só traz os estados usados; o nome do processo é escapado
+	texto := string(saida)
+	if strings.Contains(texto, estadoBloqueado) || !strings.Contains(texto, "troca de contexto (2)") {
+		t.Errorf("legenda inesperada:\n%s", texto)
+	}
+	if strings.Contains(texto, "<B>") {
+		t.Error("o nome do processo não foi escapado")
+	}
+}
+
+func TestParametrosGantt(t *testing.T) {
+	casos := []struct {
+		url     string
+		body    ContextBody
+		invalid bool
+	}{
+		{"/gantt?alg=rr&quantum=2&input=0:5:2,0:2:3", ContextBody{Alg: "rr", Quantum: 2, Input: []Processes{
+			{Begin: 0, Duration: 5, Priority: 2}, {Begin: 0, Duration: 2, Priority: 3}}}, false},
+		{"/gantt?alg=fcfs&input=1:3:1,", ContextBody{Alg: "fcfs", Quantum: 1, Input: []Processes{{Begin: 1, Duration: 3, Priority: 1}}}, false},
+		{"/gantt?alg=rr&input=0:5", ContextBody{}, true},
+		{"/gantt?alg=rr&quantum=x&input=0:5:1", ContextBody{}, true},
+	}
+
+	for _, caso := range casos {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", caso.url, nil)
+
+		body, err := lerParametrosURL(c)
+		if caso.invalid {
+			if err == nil {
+				t.Errorf("%s: deveria ser inválido", caso.url)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", caso.url, err)
+			continue
+		}
+		if body.Alg != caso.body.Alg || body.Quantum != caso.body.Quantum || !reflect.DeepEqual(body.Input, caso.body.Input) {
+			t.Errorf("%s: %+v, esperado %+v", caso.url, body, caso.body)
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Modelos de threads aceitos no campo threadModel
+const (
+	modeloUmParaUm         = "1:1" // Cada thread é uma entidade do kernel, escalonada pela política escolhida (padrão)
+	modeloMuitosParaUm     = "N:1" // As threads do processo dividem uma única entidade do kernel
+	modeloMuitosParaMuitos = "M:N" // As threads do processo dividem kernelThreads entidades do kernel
+)
+
+// Escalonadores de usuário aceitos no campo userScheduler
+const (
+	escalonadorUsuarioFIFO = "fifo" // A thread executa até terminar; depois vem a próxima da entidade (padrão)
+	escalonadorUsuarioRR   = "rr"   // As threads da entidade se revezam a cada userQuantum unidades executadas
+)
+
+// Entidades do kernel por processo no modelo M:N quando kernelThreads não é informado
+const entidadesPadraoMN = 2
+
+// ThreadSpec é uma thread de um processo da carga, com a sua rajada de CPU
+type ThreadSpec struct {
+	Name     string `json:"name,omitempty"` // Nome opcional; o padrão é t<posição>
+	Duration int    `json:"duration"`
+}
+
+// Thread é uma thread de usuário executada por uma entidade do kernel
+type Thread struct {
+	nome           string // <processo>.<thread>
+	processo       string // Rótulo do processo da carga
+	indiceProcesso int    // Posição do processo na carga
+	entidade       *Processo
+	chegada        int
+	duracao        int
+	restante       int
+	inicio         int // -1 = ainda não executou
+	termino        int // -1 = ainda não terminou
+	indiceExecucao int // Posição do último intervalo de execução da thread (-1 = nenhum)
+}
+
+// ControleThreads guarda o escalonador de usuário e as threads das entidades do kernel
+type ControleThreads struct {
+	modelo      string
+	entidades   int // Entidades do kernel por processo no modelo M:N
+	escalonador string
+	quantum     int
+	threads     []*Thread // Na ordem da carga
+	execucoes   []ExecucaoThread
+}
+
+// ExecucaoThread é um intervalo contínuo em que a thread executou na entidade do kernel
+type ExecucaoThread struct {
+	Thread   string `json:"thread"`
+	Entidade string `json:"entidade"`
+	Inicio   int    `json:"inicio"`
+	Fim      int    `json:"fim"`
+}
+
+// MetricasThread guarda os tempos de uma thread ao fim da simulação
+type MetricasThread struct {
+	Thread        string `json:"thread"`
+	Processo      string `json:"processo"`
+	Entidade      string `json:"entidade"` // Entidade do kernel que executou a thread
+	Chegada       int    `json:"chegada"`
+	Duracao       int    `json:"duracao"`
+	Inicio        int    `json:"inicio"`
+	Termino       int    `json:"termino"`
+	Concluida     bool   `json:"concluida"`
+	TempoVida     int    `json:"tempoVida"`
+	TempoEspera   int    `json:"tempoEspera"`
+	TempoResposta int    `json:"tempoResposta"`
+}
+
+// MetricasProcessoThreads guarda os tempos de um processo com threads: ele termina com a última thread
+type MetricasProcessoThreads struct {
+	Processo    string `json:"processo"`
+	Threads     int    `json:"threads"`
+	Entidades   int    `json:"entidades"` // Entidades do kernel usadas pelas threads
+	Chegada     int    `json:"chegada"`
+	Duracao     int    `json:"duracao"` // Soma das rajadas das threads
+	Inicio      int    `json:"inicio"`
+	Termino     int    `json:"termino"`
+	Concluido   bool   `json:"concluido"`
+	TempoVida   int    `json:"tempoVida"`
+	TempoEspera int    `json:"tempoEspera"`
+}
+
+// ResultadoThreads separa as métricas por processo e por thread
+// As métricas principais do resultado continuam sendo das entidades do kernel
+type ResultadoThreads struct {
+	Modelo                 string                    `json:"modelo"`
+	EntidadesPorProcesso   int                       `json:"entidadesPorProcesso,omitempty"` // Presente no modelo M:N
+	EscalonadorUsuario     string                    `json:"escalonadorUsuario"`
+	QuantumUsuario         int                       `json:"quantumUsuario,omitempty"` // Presente no escalonador de usuário "rr"
+	Processos              []MetricasProcessoThreads `json:"processos"`
+	Threads                []MetricasThread          `json:"threads"`
+	Execucoes              []ExecucaoThread          `json:"execucoes"`
+	TempoMedioVidaProcesso float64                   `json:"tempoMedioVidaProcesso"` // Médias dos concluídos
+	TempoMedioVidaThread   float64                   `json:"tempoMedioVidaThread"`
+	TempoMedioEsperaThread float64                   `json:"tempoMedioEsperaThread"`
+}
+
+// modeloDeThreads devolve o modelo pedido em threadModel, com o padrão 1:1
+func modeloDeThreads(body ContextBody) string {
+	if body.ThreadModel == "" {
+		return modeloUmParaUm
+	}
+	return body.ThreadModel
+}
+
+// validarThreads confere as threads dos processos e as opções do modelo de threads
+func validarThreads(body ContextBody) error {
+	usadas := false
+	for i, entrada := range body.Input {
+		if len(entrada.Threads) == 0 {
+			continue
+		}
+		usadas = true
+		if err := validarThreadsDoProcesso(entrada); err != nil {
+			return fmt.Errorf("Processo %d: %v", i+1, err)
+		}
+	}
+
+	if !usadas {
+		if body.ThreadModel != "" || body.KernelThreads != 0 || body.UserScheduler != "" || body.UserQuantum != 0 {
+			return fmt.Errorf("threadModel, kernelThreads, userScheduler e userQuantum exigem processos com threads")
+		}
+		return nil
+	}
+	if body.MemorySize > 0 {
+		return fmt.Errorf("threads não podem ser usadas com memorySize")
+	}
+
+	switch modeloDeThreads(body) {
+	case modeloUmParaUm, modeloMuitosParaUm:
+		if body.KernelThreads != 0 {
+			return fmt.Errorf("kernelThreads só se aplica ao modelo %q", modeloMuitosParaMuitos)
+		}
+	case modeloMuitosParaMuitos:
+		if body.KernelThreads < 0 {
+			return fmt.Errorf("kernelThreads deve ser positivo")
+		}
+	default:
+		return fmt.Errorf("threadModel inválido: use %q, %q ou %q", modeloUmParaUm, modeloMuitosParaUm, modeloMuitosParaMuitos)
+	}
+
+	switch body.UserScheduler {
+	case "", escalonadorUsuarioFIFO:
+		if body.UserQuantum != 0 {
+			return fmt.Errorf("userQuantum só se aplica ao userScheduler %q", escalonadorUsuarioRR)
+		}
+	case escalonadorUsuarioRR:
+		if body.UserQuantum < 0 {
+			return fmt.Errorf("userQuantum deve ser positivo")
+		}
+	default:
+		return fmt.Errorf("userScheduler inválido: use %q ou %q", escalonadorUsuarioFIFO, escalonadorUsuarioRR)
+	}
+	return nil
+}
+
+// validarThreadsDoProcesso confere as threads de um processo da carga
+func validarThreadsDoProcesso(p Processes) error {
+	if len(p.Program) > 0 || len(p.CriticalSections) > 0 {
+		return fmt.Errorf("threads não podem ser usadas com program nem com criticalSections")
+	}
+	if p.Duration != 0 && p.Duration != duracaoDaEntrada(p) {
+		return fmt.Errorf("duration deve ser omitida ou igual à soma das threads (%d)", duracaoDaEntrada(p))
+	}
+
+	nomes := map[string]bool{}
+	for j, t := range p.Threads {
+		if t.Duration <= 0 {
+			return fmt.Errorf("a thread %d precisa de duration positiva", j+1)
+		}
+		nome := nomeDaThread(t, j)
+		if nomes[nome] {
+			return fmt.Errorf("a thread %q aparece mais de uma vez", nome)
+		}
+		nomes[nome] = true
+	}
+	return nil
+}
+
+// nomeDaThread devolve o nome da thread na carga, com o padrão t<posição>
+func nomeDaThread(t ThreadSpec, indice int) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return fmt.Sprintf("t%d", indice+1)
+}
+
+// dividirEmThreads troca o processo da carga pelas entidades do kernel do modelo de threads,
+// cada uma com as threads que ela executa; os demais dados do processo são copiados
+func dividirEmThreads(base *Processo, entrada Processes, indice int, body ContextBody) []*Processo {
+	rotulo := entrada.Name
+	if rotulo == "" {
+		rotulo = fmt.Sprintf("P%d", indice+1)
+	}
+
+	threads := make([]*Thread, len(entrada.Threads))
+	for j, spec := range entrada.Threads {
+		threads[j] = &Thread{
+			nome:           rotulo + "." + nomeDaThread(spec, j),
+			processo:       rotulo,
+			indiceProcesso: indice,
+			chegada:        base.instanteCriacao,
+			duracao:        spec.Duration,
+			restante:       spec.Duration,
+			inicio:         -1,
+			termino:        -1,
+			indiceExecucao: -1,
+		}
+	}
+
+	// Cada grupo de threads vira uma entidade do kernel
+	var grupos [][]*Thread
+	var nomes []string
+	switch modeloDeThreads(body) {
+	case modeloUmParaUm:
+		for _, t := range threads {
+			grupos = append(grupos, []*Thread{t})
+			nomes = append(nomes, t.nome)
+		}
+	case modeloMuitosParaUm:
+		grupos = [][]*Thread{threads}
+		nomes = []string{rotulo}
+	case modeloMuitosParaMuitos:
+		k := body.KernelThreads
+		if k == 0 {
+			k = entidadesPadraoMN
+		}
+		k = min(k, len(threads))
+
+		// A thread mais longa vai para a entidade menos carregada; cada entidade mantém a ordem da carga
+		grupos = make([][]*Thread, k)
+		carga := make([]int, k)
+		porDuracao := slices.Clone(threads)
+		slices.SortStableFunc(porDuracao, func(a, b *Thread) int { return cmp.Compare(b.duracao, a.duracao) })
+		for _, t := range porDuracao {
+			e := slices.Index(carga, slices.Min(carga))
+			grupos[e] = append(grupos[e], t)
+			carga[e] += t.duracao
+		}
+		for e := range grupos {
+			slices.SortFunc(grupos[e], func(a, b *Thread) int {
+				return cmp.Compare(slices.Index(threads, a), slices.Index(threads, b))
+			})
+			nomes = append(nomes, fmt.Sprintf("%s.k%d", rotulo, e+1))
+		}
+	}
+
+	entidades := make([]*Processo, len(grupos))
+	for e, grupo := range grupos {
+		entidade := *base
+		entidade.nome = nomes[e]
+		if len(grupos) > 1 {
+			entidade.idExterno = "" // O id estável identifica o processo, não cada entidade
+		}
+		entidade.threads = grupo
+		entidade.duracao = 0
+		for _, t := range grupo {
+			entidade.duracao += t.duracao
+			t.entidade = &entidade
+		}
+		entidade.tempoRestante = entidade.duracao
+		entidades[e] = &entidade
+	}
+	return entidades
+}
+
+// novoControleThreads junta as threads das entidades do kernel
+// Sem threads não há controle: cada processo é uma única rajada de CPU
+func novoControleThreads(body ContextBody, processos []*Processo) *ControleThreads {
+	var threads []*Thread
+	for _, p := range processos {
+		threads = append(threads, p.threads...)
+	}
+	if len(threads) == 0 {
+		return nil
+	}
+
+	// As entidades estão na ordem de chegada; as threads voltam à ordem da carga
+	slices.SortStableFunc(threads, func(a, b *Thread) int { return cmp.Compare(a.indiceProcesso, b.indiceProcesso) })
+
+	c := &ControleThreads{modelo: modeloDeThreads(body), escalonador: body.UserScheduler, quantum: body.UserQuantum, threads: threads}
+	if c.modelo == modeloMuitosParaMuitos {
+		c.entidades = cmp.Or(body.KernelThreads, entidadesPadraoMN)
+	}
+	if c.escalonador == "" {
+		c.escalonador = escalonadorUsuarioFIFO
+	}
+	if c.escalonador == escalonadorUsuarioRR && c.quantum == 0 {
+		c.quantum = 1
+	}
+	return c
+}
+
+// executarThread é o escalonador de usuário: conta a unidade que a entidade do kernel acabou de
+// executar para a thread escolhida e registra o intervalo de execução
+// Deve ser chamada com o relógio já avançado
+func (s *Simulador) executarThread(entidade *Processo) {
+	c := s.threads
+	t := c.escolherThread(entidade)
+
+	if t.inicio == -1 {
+		t.inicio = s.tempoAtual - 1
+	}
+	t.restante--
+	entidade.fatiaThread++
+	if t.restante == 0 {
+		t.termino = s.tempoAtual
+	}
+
+	// Estende o intervalo anterior se a thread executou no instante imediatamente antes
+	if t.indiceExecucao >= 0 && c.execucoes[t.indiceExecucao].Fim == s.tempoAtual-1 {
+		c.execucoes[t.indiceExecucao].Fim = s.tempoAtual
+		return
+	}
+	t.indiceExecucao = len(c.execucoes)
+	c.execucoes = append(c.execucoes, ExecucaoThread{Thread: t.nome, Entidade: entidade.rotulo(), Inicio: s.tempoAtual - 1, Fim: s.tempoAtual})
+}
+
+// escolherThread mantém a thread atual da entidade enquanto ela tiver trabalho (e, no "rr", fatia);
+// senão passa para a próxima thread com trabalho, em ordem circular
+func (c *ControleThreads) escolherThread(entidade *Processo) *Thread {
+	atual := entidade.threads[entidade.threadAtual]
+	if atual.restante > 0 && (c.escalonador == escalonadorUsuarioFIFO || entidade.fatiaThread < c.quantum) {
+		return atual
+	}
+
+	n := len(entidade.threads)
+	for k := 1; k <= n; k++ {
+		i := (entidade.threadAtual + k) % n
+		if entidade.threads[i].restante > 0 {
+			entidade.threadAtual = i
+			entidade.fatiaThread = 0
+			return entidade.threads[i]
+		}
+	}
+	return atual
+}
+
+// resultado calcula as métricas por thread e por processo ao fim da simulação
+func (c *ControleThreads) resultado(casasDecimais int) *ResultadoThreads {
+	r := &ResultadoThreads{
+		Modelo:               c.modelo,
+		EntidadesPorProcesso: c.entidades,
+		EscalonadorUsuario:   c.escalonador,
+		QuantumUsuario:       c.quantum,
+		Threads:              make([]MetricasThread, 0, len(c.threads)),
+		Execucoes:            c.execucoes,
+	}
+	if r.Execucoes == nil {
+		r.Execucoes = []ExecucaoThread{}
+	}
+
+	somaVidaThread, somaEsperaThread, concluidas := 0, 0, 0
+	posicao := map[int]int{}               // Posição de cada processo da carga em r.Processos
+	entidades := map[int]map[string]bool{} // Entidades do kernel usadas por cada processo
+	for _, t := range c.threads {
+		m := MetricasThread{
+			Thread:   t.nome,
+			Processo: t.processo,
+			Entidade: t.entidade.rotulo(),
+			Chegada:  t.chegada,
+			Duracao:  t.duracao,
+			Inicio:   t.inicio,
+			Termino:  t.termino,
+		}
+		if t.termino >= 0 {
+			m.Concluida = true
+			m.TempoVida = t.termino - t.chegada
+			m.TempoEspera = m.TempoVida - t.duracao
+			m.TempoResposta = t.inicio - t.chegada
+			somaVidaThread += m.TempoVida
+			somaEsperaThread += m.TempoEspera
+			concluidas++
+		}
+		r.Threads = append(r.Threads, m)
+
+		// O processo termina com a última thread e começa com a primeira
+		if _, ok := posicao[t.indiceProcesso]; !ok {
+			posicao[t.indiceProcesso] = len(r.Processos)
+			entidades[t.indiceProcesso] = map[string]bool{}
+			r.Processos = append(r.Processos, MetricasProcessoThreads{Processo: t.processo, Chegada: t.chegada, Inicio: -1, Concluido: true})
+		}
+		p := &r.Processos[posicao[t.indiceProcesso]]
+		entidades[t.indiceProcesso][m.Entidade] = true
+		p.Entidades = len(entidades[t.indiceProcesso])
+		p.Threads++
+		p.Duracao += t.duracao
+		if t.inicio >= 0 && (p.Inicio == -1 || t.inicio < p.Inicio) {
+			p.Inicio = t.inicio
+		}
+		p.Concluido = p.Concluido && m.Concluida
+		p.Termino = max(p.Termino, t.termino)
+	}
+
+	somaVidaProcesso, concluidos := 0, 0
+	for i := range r.Processos {
+		p := &r.Processos[i]
+		if !p.Concluido {
+			p.Termino = -1
+			continue
+		}
+		p.TempoVida = p.Termino - p.Chegada
+		p.TempoEspera = p.TempoVida - p.Duracao
+		somaVidaProcesso += p.TempoVida
+		concluidos++
+	}
+
+	if concluidos > 0 {
+		r.TempoMedioVidaProcesso = arredondar(float64(somaVidaProcesso)/float64(concluidos), casasDecimais)
+	}
+	if concluidas > 0 {
+		r.TempoMedioVidaThread = arredondar(float64(somaVidaThread)/float64(concluidas), casasDecimais)
+		r.TempoMedioEsperaThread = arredondar(float64(somaEsperaThread)/float64(concluidas), casasDecimais)
+	}
+	return r
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// threadsDeTeste cria as especificações das threads com as durações informadas
+func threadsDeTeste(duracoes ...int) []ThreadSpec {
+	specs := make([]ThreadSpec, len(duracoes))
+	for i, d := range duracoes {
+		specs[i] = ThreadSpec{Duration: d}
+	}
+	return specs
+}
+
+func TestBalanceamentoMN(t *testing.T) {
+	casos := []struct {
+		entidades int
+		duracoes  []int
+		grupos    [][]string // Threads de cada entidade, na ordem da carga
+		cargas    []int
+	}{
+		// A mais longa vai para a entidade menos carregada: 5, 4, 3 (k2), 2 (k1) e 1 (empate, k1)
+		{2, []int{5, 1, 4, 2, 3}, [][]string{{"A.t1", "A.t2", "A.t4"}, {"A.t3", "A.t5"}}, []int{8, 7}},
+		{3, []int{5, 1, 4, 2, 3}, [][]string{{"A.t1"}, {"A.t2", "A.t3"}, {"A.t4", "A.t5"}}, []int{5, 5, 5}},
+		// Mais entidades do que threads: uma entidade por thread
+		{4, []int{2, 3}, [][]string{{"A.t2"}, {"A.t1"}}, []int{3, 2}},
+	}
+
+	for _, c := range casos {
+		entrada := Processes{Name: "A", Threads: threadsDeTeste(c.duracoes...)}
+		body := ContextBody{ThreadModel: modeloMuitosParaMuitos, KernelThreads: c.entidades}
+		entidades := dividirEmThreads(&Processo{tempoInicio: -1, tempoTermino: -1}, entrada, 0, body)
+
+		if len(entidades) != len(c.grupos) {
+			t.Fatalf("%d entidades com kernelThreads %d, esperado %d", len(entidades), c.entidades, len(c.grupos))
+		}
+		for e, entidade := range entidades {
+			var nomes []string
+			for _, th := range entidade.threads {
+				nomes = append(nomes, th.nome)
+				if th.entidade != entidade {
+					t.Errorf("%s aponta para outra entidade", th.nome)
+				}
+			}
+			if !slices.Equal(nomes, c.grupos[e]) || entidade.duracao != c.cargas[e] || entidade.tempoRestante != c.cargas[e] {
+				t.Errorf("kernelThreads %d, entidade %s: threads %v com duração %d, esperado %v com %d",
+					c.entidades, entidade.nome, nomes, entidade.duracao, c.grupos[e], c.cargas[e])
+			}
+		}
+	}
+}
+
+func TestEscalonadorUsuarioRR(t *testing.T) {
+	body := ContextBody{Alg: "fcfs", Quantum: 1, ThreadModel: modeloMuitosParaUm, UserScheduler: escalonadorUsuarioRR, UserQuantum: 2, Input: []Processes{
+		{Begin: 0, Priority: 1, Name: "A", Threads: threadsDeTeste(3, 2)},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A única entidade do kernel reveza as threads a cada 2 unidades
+	esperado := []ExecucaoThread{
+		{Thread: "A.t1", Entidade: "A", Inicio: 0, Fim: 2},
+		{Thread: "A.t2", Entidade: "A", Inicio: 2, Fim: 4},
+		{Thread: "A.t1", Entidade: "A", Inicio: 4, Fim: 5},
+	}
+	if !slices.Equal(r.Threads.Execucoes, esperado) {
+		t.Errorf("execuções %+v, esperado %+v", r.Threads.Execucoes, esperado)
+	}
+	if len(r.Processos) != 1 || r.Processos[0].Termino != 5 {
+		t.Errorf("a entidade do kernel deveria terminar em 5: %+v", r.Processos)
+	}
+
+	// Com o "fifo", cada thread executa até o fim
+	body.UserScheduler, body.UserQuantum = escalonadorUsuarioFIFO, 0
+	if r, err = processScheduler(context.Background(), body, nil); err != nil {
+		t.Fatal(err)
+	}
+	esperado = []ExecucaoThread{
+		{Thread: "A.t1", Entidade: "A", Inicio: 0, Fim: 3},
+		{Thread: "A.t2", Entidade: "A", Inicio: 3, Fim: 5},
+	}
+	if !slices.Equal(r.Threads.Execucoes, esperado) {
+		t.Errorf("execuções com fifo %+v, esperado %+v", r.Threads.Execucoes, esperado)
+	}
+}
+
+func TestProcessoTerminaComUltimaThread(t *testing.T) {
+	body := ContextBody{Alg: "rr", Quantum: 1, Input: []Processes{
+		{Begin: 0, Priority: 1, Name: "A", Threads: threadsDeTeste(4, 2)},
+		{Begin: 1, Duration: 2, Priority: 1, Name: "B"},
+	}}
+	r, err := processScheduler(context.Background(), body, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	terminos := map[string]int{}
+	for _, th := range r.Threads.Threads {
+		terminos[th.Thread] = th.Termino
+	}
+	if terminos["A.t1"] != 8 || terminos["A.t2"] != 5 {
+		t.Errorf("términos das threads %v, esperado A.t1 em 8 e A.t2 em 5", terminos)
+	}
+
+	esperado := MetricasProcessoThreads{
+		Processo: "A", Threads: 2, Entidades: 2, Chegada: 0, Duracao: 6,
+		Inicio: 0, Termino: 8, Concluido: true, TempoVida: 8, TempoEspera: 2,
+	}
+	if len(r.Threads.Processos) != 1 || r.Threads.Processos[0] != esperado {
+		t.Errorf("métricas do processo %+v, esperado %+v", r.Threads.Processos, esperado)
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+// WorstFit usa a maior lacuna, para que a sobra ainda seja grande o bastante para outros processos
+// Empates ficam com a lacuna de menor endereço
+type WorstFit struct {
+	s *SimuladorAlocacao
+}
+
+func (alg *WorstFit) alocar(tamanho int) int {
+	escolhida := -1
+	for i, seg := range alg.s.segmentos {
+		if seg.livre() && seg.tamanho >= tamanho &&
+			(escolhida == -1 || seg.tamanho > alg.s.segmentos[escolhida].tamanho) {
+			escolhida = i
+		}
+	}
+	if escolhida == -1 {
+		return -1
+	}
+	return alg.s.dividir(escolhida, tamanho)
+}
+
+func (alg *WorstFit) liberar(i int) {
+	alg.s.juntarVizinhos(i)
+}